@@ -0,0 +1,312 @@
+package main
+
+import (
+  "encoding/csv"
+  "encoding/json"
+  "flag"
+  "fmt"
+  "io"
+  "os"
+  "strconv"
+  "strings"
+  "time"
+
+  "shubhcron-pandit/pandit"
+)
+
+// One vedic day of the almanac, times are empty when the sun does not rise or set
+type almanacDay struct {
+  Date        string          `json:"date"`
+  Vaar        string          `json:"vaar"`
+  Sunrise     string          `json:"sunrise"`
+  Sunset      string          `json:"sunset"`
+  NextSunrise string          `json:"nextSunrise"`
+  SolarNoon   string          `json:"solarNoon"`
+  Fallback    string          `json:"fallback,omitempty"`
+  Periods     []almanacPeriod `json:"periods"`
+  // The table engine's columns of the CSV
+  table       []string
+}
+
+type almanacPeriod struct {
+  Name  string `json:"name"`
+  Phase string `json:"phase"`
+  Start string `json:"start"`
+  End   string `json:"end"`
+  Shubh bool   `json:"shubh"`
+  Kaal  string `json:"kaal,omitempty"`
+}
+
+type almanac struct {
+  Year      int          `json:"year"`
+  City      string       `json:"city,omitempty"`
+  Latitude  float64      `json:"latitude"`
+  Longitude float64      `json:"longitude"`
+  Timezone  string       `json:"timezone"`
+  Policy    string       `json:"policy"`
+  Engine    string       `json:"engine"`
+  Days      []almanacDay `json:"days"`
+}
+
+/**
+ * pandit almanac --year 2027 --city Mumbai --format csv|json|sqlite
+ * Precomputes every vedic day of a year. The CSV can also be
+ * loaded by the table engine through SUN_TABLE. There is no sqlite
+ * driver vendored, so sqlite (or sql) is a script to feed the
+ * sqlite3 shell rather than a database file
+ */
+func runAlmanac(args []string) error {
+  flags := flag.NewFlagSet("almanac", flag.ExitOnError)
+  year := flags.Int("year", time.Now().Year(), "calendar year")
+  cityName := flags.String("city", os.Getenv("CITY"), "city from the gazetteer, otherwise LATITUDE and LONGITUDE are used")
+  tz := flags.String("tz", "", "IANA timezone when not using a city, defaults to the local one")
+  format := flags.String("format", "csv", "csv, json, or sqlite (also sql) for a SQL script to run with sqlite3 almanac.db < almanac.sql, no .db file is written")
+  output := flags.String("output", "", "file to write to instead of stdout")
+  policyName := flags.String("policy", os.Getenv("SHUBH_POLICY"), "policy deciding which periods are shubh")
+  engineName := flags.String("engine", os.Getenv("SHUBH_ENGINE"), "kelvins, noaa, or table with SUN_TABLE set")
+  polarName := flags.String("polar", os.Getenv("SHUBH_POLAR"), "none, clamp or civil, for days without sunrise or sunset")
+  flags.Parse(args)
+
+  calculator, city, err := almanacCalculator(*cityName, *tz, *policyName, *engineName, *polarName)
+  if err != nil {
+    return err
+  }
+
+  a := almanac{
+    Year:      *year,
+    City:      city,
+    Latitude:  calculator.Location.Latitude,
+    Longitude: calculator.Location.Longitude,
+    Timezone:  calculator.Location.Timezone.String(),
+    Policy:    calculator.Policy.String(),
+    Engine:    calculator.Engine.Name(),
+  }
+  start := time.Date(*year, time.January, 1, 0, 0, 0, 0, calculator.Location.Timezone)
+  for date := start; date.Year() == *year; date = date.AddDate(0, 0, 1) {
+    day, err := almanacDayFor(calculator, date)
+    if err != nil {
+      return err
+    }
+    a.Days = append(a.Days, day)
+  }
+
+  var w io.Writer = os.Stdout
+  if *output != "" {
+    f, err := os.Create(*output)
+    if err != nil {
+      return err
+    }
+    defer f.Close()
+    w = f
+  }
+
+  switch *format {
+  case "csv":
+    return writeAlmanacCSV(w, a)
+  case "json":
+    encoder := json.NewEncoder(w)
+    encoder.SetIndent("", "  ")
+    return encoder.Encode(a)
+  case "sqlite", "sql":
+    return writeAlmanacSQL(w, a)
+  }
+  return fmt.Errorf("unknown format %q, expected csv, json or sqlite", *format)
+}
+
+func almanacCalculator(cityName string, tz string, policyName string, engineName string, polarName string) (*pandit.Calculator, string, error) {
+  location, err := pandit.LocationFromEnv()
+  if err != nil {
+    return nil, "", err
+  }
+  timezone := time.Local
+  if cityName != "" {
+    city, err := pandit.LookupCity(cityName)
+    if err != nil {
+      return nil, "", err
+    }
+    location = city.Location()
+    cityName = city.Name
+    if timezone, err = city.TimeLocation(); err != nil {
+      return nil, "", err
+    }
+  }
+  if tz != "" {
+    if timezone, err = time.LoadLocation(tz); err != nil {
+      return nil, "", err
+    }
+  }
+  location.Timezone = timezone
+
+  policy, err := pandit.ParsePolicy(policyName)
+  if err != nil {
+    return nil, "", err
+  }
+  calculator, err := pandit.NewCalculator(location, policy)
+  if err != nil {
+    return nil, "", err
+  }
+  if err := pandit.RegisterEnginesFromEnv(); err != nil {
+    return nil, "", err
+  }
+  if calculator.Engine, err = pandit.ParseEngine(engineName); err != nil {
+    return nil, "", err
+  }
+  if calculator.Polar, err = pandit.ParsePolarFallback(polarName); err != nil {
+    return nil, "", err
+  }
+  if calculator.Definition, err = pandit.SunriseDefinitionFromEnv(); err != nil {
+    return nil, "", err
+  }
+  return calculator, cityName, nil
+}
+
+/**
+ * The vedic day starting at sunrise on date. Days without
+ * a sunrise are kept, with empty times and no periods
+ */
+func almanacDayFor(calculator *pandit.Calculator, date time.Time) (almanacDay, error) {
+  day := almanacDay{
+    Date:    date.Format("2006-01-02"),
+    Vaar:    pandit.Vaar(date.Weekday()).String(),
+    Periods: []almanacPeriod{},
+  }
+
+  // Under the standard definition and without the polar fallback,
+  // which the table engine applies itself when it loads them
+  table, err := pandit.SunTableRecord(calculator.Engine, calculator.Location, date)
+  if err != nil {
+    return day, err
+  }
+  day.table = table
+
+  periods, err := calculator.Schedule(date)
+  if pandit.Cause(err) == pandit.ErrNoSunrise {
+    return day, nil
+  }
+  if err != nil {
+    return day, err
+  }
+  times, err := calculator.SunTimes(date)
+  if err == nil {
+    day.SolarNoon = formatTime(times.SolarNoon)
+  } else if pandit.Cause(err) != pandit.ErrNoSunrise {
+    return day, err
+  }
+
+  day.Vaar = periods[0].Vaar.String()
+  day.Sunrise = formatTime(periods[0].Start)
+  day.Sunset = formatTime(periods[8].Start)
+  day.NextSunrise = formatTime(periods[len(periods)-1].End)
+  if periods[0].Fallback != pandit.FallbackNone {
+    day.Fallback = periods[0].Fallback.String()
+  }
+  for _, period := range periods {
+    day.Periods = append(day.Periods, almanacPeriod{
+      Name:  period.Chowgadhiya.String(),
+      Phase: period.Phase.String(),
+      Start: formatTime(period.Start),
+      End:   formatTime(period.End),
+      Shubh: calculator.Policy.IsShubh(period),
+      Kaal:  kaalName(period.Kaal),
+    })
+  }
+  return day, nil
+}
+
+func kaalName(k pandit.KaalType) string {
+  if k == pandit.NoKaal {
+    return ""
+  }
+  return k.String()
+}
+
+func formatTime(t time.Time) string {
+  return t.Format(time.RFC3339)
+}
+
+/**
+ * One row per day. The first columns are the ones the table
+ * engine reads, with the standard sunrise and no polar fallback
+ * so that loading them gives back what any engine would. The
+ * rest follow the sunrise definition and polar fallback in use:
+ * the next sunrise, then each period's name and start time
+ */
+func writeAlmanacCSV(w io.Writer, a almanac) error {
+  writer := csv.NewWriter(w)
+  header := append([]string{}, pandit.TABLE_COLUMNS...)
+  header = append(header, "vaar", "next_sunrise", "fallback")
+  for _, phase := range []pandit.Phase{pandit.Day, pandit.Night} {
+    for i := 1; i <= 8; i++ {
+      column := phase.String() + "_" + strconv.Itoa(i)
+      header = append(header, column, column+"_start")
+    }
+  }
+  if err := writer.Write(header); err != nil {
+    return err
+  }
+
+  for _, day := range a.Days {
+    record := append([]string{}, day.table...)
+    record = append(record, day.Vaar, day.NextSunrise, day.Fallback)
+    for _, period := range day.Periods {
+      record = append(record, period.Name, period.Start)
+    }
+    for len(record) < len(header) {
+      record = append(record, "")
+    }
+    if err := writer.Write(record); err != nil {
+      return err
+    }
+  }
+  writer.Flush()
+  return writer.Error()
+}
+
+/**
+ * A script for the sqlite3 shell: sqlite3 almanac.db < almanac.sql
+ */
+func writeAlmanacSQL(w io.Writer, a almanac) error {
+  lines := []string{
+    "BEGIN TRANSACTION;",
+    "CREATE TABLE IF NOT EXISTS meta (key TEXT PRIMARY KEY, value TEXT);",
+    "CREATE TABLE IF NOT EXISTS days (date TEXT PRIMARY KEY, vaar TEXT, sunrise TEXT, sunset TEXT, next_sunrise TEXT, solar_noon TEXT, fallback TEXT);",
+    "CREATE TABLE IF NOT EXISTS periods (date TEXT REFERENCES days(date), position INTEGER, phase TEXT, name TEXT, start TEXT, end TEXT, shubh INTEGER, kaal TEXT, PRIMARY KEY (date, position));",
+  }
+  meta := [][2]string{
+    {"year", strconv.Itoa(a.Year)},
+    {"city", a.City},
+    {"latitude", strconv.FormatFloat(a.Latitude, 'f', -1, 64)},
+    {"longitude", strconv.FormatFloat(a.Longitude, 'f', -1, 64)},
+    {"timezone", a.Timezone},
+    {"policy", a.Policy},
+    {"engine", a.Engine},
+  }
+  for _, m := range meta {
+    lines = append(lines, fmt.Sprintf("INSERT OR REPLACE INTO meta VALUES (%s, %s);", sqlString(m[0]), sqlString(m[1])))
+  }
+  for _, day := range a.Days {
+    lines = append(lines, fmt.Sprintf("INSERT OR REPLACE INTO days VALUES (%s, %s, %s, %s, %s, %s, %s);",
+      sqlString(day.Date), sqlString(day.Vaar), sqlString(day.Sunrise), sqlString(day.Sunset),
+      sqlString(day.NextSunrise), sqlString(day.SolarNoon), sqlString(day.Fallback)))
+    for i, period := range day.Periods {
+      shubh := 0
+      if period.Shubh {
+        shubh = 1
+      }
+      lines = append(lines, fmt.Sprintf("INSERT OR REPLACE INTO periods VALUES (%s, %d, %s, %s, %s, %s, %d, %s);",
+        sqlString(day.Date), i+1, sqlString(period.Phase), sqlString(period.Name), sqlString(period.Start), sqlString(period.End), shubh, sqlString(period.Kaal)))
+    }
+  }
+  lines = append(lines, "COMMIT;")
+
+  _, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+  return err
+}
+
+// Empty strings become NULL
+func sqlString(value string) string {
+  if value == "" {
+    return "NULL"
+  }
+  return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+  "bytes"
+  "testing"
+  "time"
+
+  "shubhcron-pandit/pandit"
+)
+
+/**
+ * Writes a few days of almanac under a sunrise definition and a
+ * polar fallback, loads the CSV back into the table engine, and
+ * checks it gives what the engine that wrote it gives
+ */
+func TestAlmanacTableRoundTrip(t *testing.T) {
+  definition := pandit.SunriseDefinition{Limb: pandit.Centre, Elevation: 2000}
+  for _, name := range []string{"Shimla", "Tromso"} {
+    city, err := pandit.LookupCity(name)
+    if err != nil {
+      t.Fatal(err)
+    }
+    timezone, err := city.TimeLocation()
+    if err != nil {
+      t.Fatal(err)
+    }
+    location := city.Location()
+    location.Timezone = timezone
+    newCalculator := func(engine pandit.SunCalculator) *pandit.Calculator {
+      calculator, err := pandit.NewCalculator(location, pandit.DefaultPolicy)
+      if err != nil {
+        t.Fatal(err)
+      }
+      calculator.Engine = engine
+      calculator.Definition = definition
+      calculator.Polar = pandit.FallbackCivil
+      return calculator
+    }
+
+    calculator := newCalculator(pandit.NOAASunCalculator{})
+    a := almanac{Latitude: location.Latitude, Longitude: location.Longitude}
+    // Shimla's spring, and Tromso's polar night, deep enough that
+    // no definition has a sunrise. The last day is only there for
+    // the next sunrise of the one before
+    dates, written := []time.Time{}, []time.Time{}
+    for _, start := range []time.Time{time.Date(2027, 3, 8, 0, 0, 0, 0, timezone), time.Date(2027, 12, 15, 0, 0, 0, 0, timezone)} {
+      for i := 0; i < 7; i++ {
+        if i < 6 {
+          dates = append(dates, start.AddDate(0, 0, i))
+        }
+        written = append(written, start.AddDate(0, 0, i))
+      }
+    }
+    for _, date := range written {
+      day, err := almanacDayFor(calculator, date)
+      if err != nil {
+        t.Fatal(err)
+      }
+      a.Days = append(a.Days, day)
+    }
+
+    var buffer bytes.Buffer
+    if err := writeAlmanacCSV(&buffer, a); err != nil {
+      t.Fatal(err)
+    }
+    table, err := pandit.NewTableSunCalculator(&buffer)
+    if err != nil {
+      t.Fatal(err)
+    }
+
+    loaded := newCalculator(table)
+    for _, date := range dates {
+      ours, err := calculator.Schedule(date)
+      if err != nil {
+        t.Fatal(err)
+      }
+      theirs, err := loaded.Schedule(date)
+      if err != nil {
+        t.Fatalf("%s %s: %v", name, date.Format("2006-01-02"), err)
+      }
+      for i := range ours {
+        if ours[i] != theirs[i] {
+          t.Errorf("%s %s: period %d from the table is %s %s..%s, expected %s %s..%s", name, date.Format("2006-01-02"), i+1,
+            theirs[i].Chowgadhiya, theirs[i].Start.Format("15:04:05"), theirs[i].End.Format("15:04:05"),
+            ours[i].Chowgadhiya, ours[i].Start.Format("15:04:05"), ours[i].End.Format("15:04:05"))
+          break
+        }
+      }
+    }
+  }
+}
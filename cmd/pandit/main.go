@@ -0,0 +1,57 @@
+package main
+
+import (
+  "flag"
+  "fmt"
+  "os"
+  "sort"
+)
+
+/**
+ * A subcommand gets the arguments after its name
+ * and returns an error to exit with status 1
+ */
+type command struct {
+  summary string
+  run     func(args []string) error
+}
+
+var commands = map[string]command{
+  "almanac": {"write a year of sunrises and chowgadhiyas as csv, json or a sql script", runAlmanac},
+  "verify":  {"compare timings against reference fixtures", runVerify},
+}
+
+func printHelp() {
+  fmt.Println("Usage: pandit command [flags]")
+  fmt.Println("Commands:")
+  names := []string{}
+  for name := range commands {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+  for _, name := range names {
+    fmt.Printf("  %-10s %s\n", name, commands[name].summary)
+  }
+  fmt.Println("Run pandit command --help for its flags")
+}
+
+func main() {
+  flag.Usage = printHelp
+  flag.Parse()
+
+  args := flag.Args()
+  if len(args) < 1 {
+    printHelp()
+    os.Exit(0)
+  }
+  cmd, ok := commands[args[0]]
+  if !ok {
+    fmt.Fprintln(os.Stderr, "unknown command", args[0])
+    printHelp()
+    os.Exit(255)
+  }
+  if err := cmd.run(args[1:]); err != nil {
+    fmt.Fprintln(os.Stderr, err)
+    os.Exit(1)
+  }
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+  "encoding/csv"
+  "flag"
+  "fmt"
+  "io"
+  "math"
+  "os"
+  "path/filepath"
+  "sort"
+  "strconv"
+  "strings"
+  "text/tabwriter"
+  "time"
+
+  "shubhcron-pandit/pandit"
+)
+
+// Published almanac timings
+const DEFAULT_FIXTURES = "testdata/panchang"
+
+// Rows an engine computed itself, to catch changes
+const DEFAULT_REGRESSION = "testdata/regression"
+
+// Almanacs print to the minute and differ a little in their model
+const ALMANAC_TOLERANCE = time.Minute
+
+// A baseline row is the engine's own output, rounded to the second
+const BASELINE_TOLERANCE = 2 * time.Second
+
+// Source of the rows an engine computed itself, as <engine>-baseline
+const BASELINE_SUFFIX = "-baseline"
+
+/**
+ * A reference timing from a fixture file. Event is sunrise, sunset,
+ * next_sunrise, or day_1..day_8 and night_1..night_8 for the start
+ * of a chowgadhiya, in which case name is the chowgadhiya expected
+ */
+type fixture struct {
+  city   string
+  date   string
+  event  string
+  name   string
+  // Local clock time in the city's timezone, HH:MM or HH:MM:SS
+  clock  string
+  source string
+}
+
+/**
+ * How far off f may be. A baseline row checked with the engine
+ * and definition that computed it is a regression check and has
+ * to agree to the second, anything else gets the tolerance
+ */
+func (f fixture) allowed(engine pandit.SunCalculator, definition pandit.SunriseDefinition, tolerance time.Duration) time.Duration {
+  if f.source == engine.Name()+BASELINE_SUFFIX && definition.IsDefault() {
+    return BASELINE_TOLERANCE
+  }
+  return tolerance
+}
+
+func (f fixture) published() bool {
+  return !strings.HasSuffix(f.source, BASELINE_SUFFIX)
+}
+
+type deviation struct {
+  fixture fixture
+  ours    time.Time
+  // Ours minus the reference
+  seconds float64
+  problem string
+  allowed time.Duration
+}
+
+func (r deviation) failed() bool {
+  return r.problem != "" || math.Abs(r.seconds) > r.allowed.Seconds()
+}
+
+/**
+ * pandit verify [--fixtures testdata/panchang] [--regression testdata/regression] [--tolerance 60s]
+ * Compares sunrise, sunset and chowgadhiya boundaries against the
+ * published timings in the fixture files and fails when any is
+ * further off than the tolerance, or a baseline row of the engine
+ * in the regression files is off by more than a second or two
+ */
+func runVerify(args []string) error {
+  flags := flag.NewFlagSet("verify", flag.ExitOnError)
+  dir := flags.String("fixtures", DEFAULT_FIXTURES, "directory of published almanac timings")
+  regression := flags.String("regression", DEFAULT_REGRESSION, "directory of engine baselines, empty to skip them")
+  tolerance := flags.Duration("tolerance", ALMANAC_TOLERANCE, "largest deviation allowed from a published almanac")
+  engineName := flags.String("engine", os.Getenv("SHUBH_ENGINE"), "kelvins, noaa, or table with SUN_TABLE set")
+  verbose := flags.Bool("v", false, "list every comparison, not only failures")
+  flags.Parse(args)
+
+  fixtures, err := loadSourcedFixtures(*dir, true)
+  if err != nil {
+    return err
+  }
+  if len(fixtures) == 0 {
+    fmt.Fprintf(os.Stderr, "no published almanac timings in %s, nothing but regressions is checked\n", *dir)
+  }
+  if *regression != "" {
+    baselines, err := loadSourcedFixtures(*regression, false)
+    if err != nil {
+      return err
+    }
+    fixtures = append(fixtures, baselines...)
+  }
+  if len(fixtures) == 0 {
+    return fmt.Errorf("no fixtures in %s or %s", *dir, *regression)
+  }
+
+  if err := pandit.RegisterEnginesFromEnv(); err != nil {
+    return err
+  }
+  engine, err := pandit.ParseEngine(*engineName)
+  if err != nil {
+    return err
+  }
+  definition, err := pandit.SunriseDefinitionFromEnv()
+  if err != nil {
+    return err
+  }
+
+  results := []deviation{}
+  for _, f := range fixtures {
+    result := verifyFixture(f, engine, definition)
+    result.allowed = f.allowed(engine, definition, *tolerance)
+    results = append(results, result)
+  }
+
+  failures, published := 0, 0
+  listed := []deviation{}
+  for _, r := range results {
+    if r.fixture.published() {
+      published++
+    }
+    if r.failed() {
+      failures++
+    }
+    if r.failed() || *verbose {
+      listed = append(listed, r)
+    }
+  }
+
+  if len(listed) > 0 {
+    w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+    fmt.Fprintln(w, "city\tdate\tevent\treference\tours\tdeviation\tallowed\tsource\t")
+    for _, r := range listed {
+      ours := r.problem
+      if ours == "" {
+        ours = r.ours.Format("15:04:05")
+      }
+      fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%+.0fs\t%s\t%s\t\n", r.fixture.city, r.fixture.date, r.fixture.event, r.fixture.clock, ours, r.seconds, r.allowed, r.fixture.source)
+    }
+    w.Flush()
+  }
+
+  printDeviationSummary(results)
+  if failures > 0 {
+    return fmt.Errorf("%d of %d timings off by more than allowed", failures, len(results))
+  }
+  fmt.Printf("all %d timings within what is allowed, %d of them from published almanacs\n", len(results), published)
+  return nil
+}
+
+/**
+ * Reads every .csv file in dir. Lines starting with # are comments,
+ * the columns are city, date, event, name, time and source
+ */
+func loadFixtures(dir string) ([]fixture, error) {
+  paths, err := filepath.Glob(filepath.Join(dir, "*.csv"))
+  if err != nil {
+    return nil, err
+  }
+  sort.Strings(paths)
+
+  fixtures := []fixture{}
+  for _, path := range paths {
+    f, err := os.Open(path)
+    if err != nil {
+      return nil, err
+    }
+    reader := csv.NewReader(f)
+    reader.Comment = '#'
+    reader.FieldsPerRecord = 6
+    for first := true; ; first = false {
+      record, err := reader.Read()
+      if err == io.EOF {
+        break
+      }
+      if err != nil {
+        f.Close()
+        return nil, fmt.Errorf("%s: %v", path, err)
+      }
+      // Header
+      if first && record[0] == "city" {
+        continue
+      }
+      fixtures = append(fixtures, fixture{
+        city:   strings.TrimSpace(record[0]),
+        date:   strings.TrimSpace(record[1]),
+        event:  strings.TrimSpace(record[2]),
+        name:   strings.TrimSpace(record[3]),
+        clock:  strings.TrimSpace(record[4]),
+        source: strings.TrimSpace(record[5]),
+      })
+    }
+    f.Close()
+  }
+  return fixtures, nil
+}
+
+/**
+ * Loads the fixtures in dir and checks they all come from a
+ * published almanac, or all from an engine's baseline
+ */
+func loadSourcedFixtures(dir string, published bool) ([]fixture, error) {
+  fixtures, err := loadFixtures(dir)
+  if err != nil {
+    return nil, err
+  }
+  for _, f := range fixtures {
+    if f.published() != published {
+      return nil, fmt.Errorf("%s: %s %s %s has source %q, keep published timings in %s and baselines in %s",
+        dir, f.city, f.date, f.event, f.source, DEFAULT_FIXTURES, DEFAULT_REGRESSION)
+    }
+    if f.source == "" {
+      return nil, fmt.Errorf("%s: %s %s %s does not name its source", dir, f.city, f.date, f.event)
+    }
+  }
+  return fixtures, nil
+}
+
+func verifyFixture(f fixture, engine pandit.SunCalculator, definition pandit.SunriseDefinition) deviation {
+  result := deviation{fixture: f}
+  fail := func(format string, args ...interface{}) deviation {
+    result.problem = fmt.Sprintf(format, args...)
+    return result
+  }
+
+  city, err := pandit.LookupCity(f.city)
+  if err != nil {
+    return fail("%v", err)
+  }
+  timezone, err := city.TimeLocation()
+  if err != nil {
+    return fail("%v", err)
+  }
+  location := city.Location()
+  location.Timezone = timezone
+  calculator, err := pandit.NewCalculator(location, pandit.DefaultPolicy)
+  if err != nil {
+    return fail("%v", err)
+  }
+  calculator.Engine = engine
+  calculator.Definition = definition
+
+  date, err := time.ParseInLocation("2006-01-02", f.date, timezone)
+  if err != nil {
+    return fail("bad date %q", f.date)
+  }
+  reference, err := parseClock(date, f.clock)
+  if err != nil {
+    return fail("bad time %q", f.clock)
+  }
+
+  switch f.event {
+  case "sunrise", "sunset", "next_sunrise":
+    day, err := calculator.VedicDayOn(date)
+    if err != nil {
+      return fail("%v", err)
+    }
+    result.ours = map[string]time.Time{"sunrise": day.Sunrise, "sunset": day.Sunset, "next_sunrise": day.NextSunrise}[f.event]
+  default:
+    index, err := periodIndex(f.event)
+    if err != nil {
+      return fail("%v", err)
+    }
+    periods, err := calculator.Schedule(date)
+    if err != nil {
+      return fail("%v", err)
+    }
+    period := periods[index]
+    if f.name != "" && period.Chowgadhiya.String() != strings.ToLower(f.name) {
+      return fail("expected %s, got %s", f.name, period.Chowgadhiya)
+    }
+    // What a lookup just after the boundary says should agree
+    current, err := calculator.Chowgadhiya(period.Start.Add(time.Second))
+    if err != nil {
+      return fail("%v", err)
+    }
+    if current.Chowgadhiya != period.Chowgadhiya {
+      return fail("schedule says %s, lookup says %s", period.Chowgadhiya, current.Chowgadhiya)
+    }
+    result.ours = period.Start
+  }
+
+  result.seconds = result.ours.Sub(reference).Seconds()
+  return result
+}
+
+// Position in the day's 16 periods of day_1..day_8 or night_1..night_8
+func periodIndex(event string) (int, error) {
+  pieces := strings.SplitN(event, "_", 2)
+  if len(pieces) == 2 {
+    n, err := strconv.Atoi(pieces[1])
+    if err == nil && n >= 1 && n <= 8 {
+      switch pieces[0] {
+      case "day":
+        return n - 1, nil
+      case "night":
+        return 8 + n - 1, nil
+      }
+    }
+  }
+  return 0, fmt.Errorf("unknown event %q", event)
+}
+
+/**
+ * The local clock time on date, or the day after for
+ * events past midnight written as 24:10 and so on
+ */
+func parseClock(date time.Time, clock string) (time.Time, error) {
+  pieces := strings.Split(clock, ":")
+  if len(pieces) < 2 || len(pieces) > 3 {
+    return time.Time{}, fmt.Errorf("expected HH:MM or HH:MM:SS")
+  }
+  values := []int{0, 0, 0}
+  for i, piece := range pieces {
+    value, err := strconv.Atoi(piece)
+    if err != nil {
+      return time.Time{}, err
+    }
+    values[i] = value
+  }
+  return time.Date(date.Year(), date.Month(), date.Day(), values[0], values[1], values[2], 0, date.Location()), nil
+}
+
+/**
+ * Largest and mean absolute deviation per kind of event
+ */
+func printDeviationSummary(results []deviation) {
+  type summary struct {
+    count int
+    total float64
+    worst float64
+  }
+  kinds := map[string]*summary{}
+  names := []string{}
+  for _, r := range results {
+    if r.problem != "" {
+      continue
+    }
+    kind := r.fixture.event
+    if _, err := periodIndex(kind); err == nil {
+      kind = "chowgadhiya"
+    }
+    s, ok := kinds[kind]
+    if !ok {
+      s = &summary{}
+      kinds[kind] = s
+      names = append(names, kind)
+    }
+    s.count++
+    s.total += math.Abs(r.seconds)
+    s.worst = math.Max(s.worst, math.Abs(r.seconds))
+  }
+  sort.Strings(names)
+
+  w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+  fmt.Fprintln(w, "event\tcount\tmean\tworst\t")
+  for _, name := range names {
+    s := kinds[name]
+    fmt.Fprintf(w, "%s\t%d\t%.1fs\t%.0fs\t\n", name, s.count, s.total/float64(s.count), s.worst)
+  }
+  w.Flush()
+}
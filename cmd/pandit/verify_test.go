@@ -0,0 +1,78 @@
+package main
+
+import (
+  "path/filepath"
+  "testing"
+  "time"
+
+  "shubhcron-pandit/pandit"
+)
+
+// Tests run in the package directory
+var TEST_FIXTURES = filepath.Join("..", "..", DEFAULT_FIXTURES)
+var TEST_REGRESSION = filepath.Join("..", "..", DEFAULT_REGRESSION)
+
+/**
+ * Published almanac timings, held to ALMANAC_TOLERANCE
+ * with both engines
+ */
+func TestPublishedFixtures(t *testing.T) {
+  fixtures, err := loadSourcedFixtures(TEST_FIXTURES, true)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(fixtures) == 0 {
+    t.Skipf("no published almanac timings in %s yet", TEST_FIXTURES)
+  }
+  checkFixtures(t, fixtures)
+}
+
+/**
+ * Engine baselines, held to BASELINE_TOLERANCE with the engine
+ * that computed them and ALMANAC_TOLERANCE with the other
+ */
+func TestRegressionFixtures(t *testing.T) {
+  fixtures, err := loadSourcedFixtures(TEST_REGRESSION, false)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(fixtures) == 0 {
+    t.Fatalf("no fixtures in %s", TEST_REGRESSION)
+  }
+  checkFixtures(t, fixtures)
+}
+
+func checkFixtures(t *testing.T, fixtures []fixture) {
+  for _, engine := range []pandit.SunCalculator{pandit.NOAASunCalculator{}, pandit.KelvinsSunCalculator{}} {
+    for _, f := range fixtures {
+      r := verifyFixture(f, engine, pandit.DefaultSunriseDefinition)
+      r.allowed = f.allowed(engine, pandit.DefaultSunriseDefinition, ALMANAC_TOLERANCE)
+      if r.failed() {
+        t.Errorf("%s: %s %s %s is %s, reference %s (%+.0fs, %s allowed, %s)", engine.Name(), f.city, f.date, f.event, r.ours.Format("15:04:05"), f.clock, r.seconds, r.allowed, r.problem)
+      }
+    }
+    t.Logf("%s: %d fixtures", engine.Name(), len(fixtures))
+  }
+}
+
+func TestFixtureTolerance(t *testing.T) {
+  noaa, kelvins := pandit.NOAASunCalculator{}, pandit.KelvinsSunCalculator{}
+  centre := pandit.SunriseDefinition{Limb: pandit.Centre}
+  tests := []struct {
+    source     string
+    engine     pandit.SunCalculator
+    definition pandit.SunriseDefinition
+    allowed    time.Duration
+  }{
+    {"noaa-baseline", noaa, pandit.DefaultSunriseDefinition, BASELINE_TOLERANCE},
+    {"noaa-baseline", kelvins, pandit.DefaultSunriseDefinition, ALMANAC_TOLERANCE},
+    {"noaa-baseline", noaa, centre, ALMANAC_TOLERANCE},
+    {"drikpanchang", noaa, pandit.DefaultSunriseDefinition, ALMANAC_TOLERANCE},
+  }
+  for _, test := range tests {
+    f := fixture{source: test.source}
+    if allowed := f.allowed(test.engine, test.definition, ALMANAC_TOLERANCE); allowed != test.allowed {
+      t.Errorf("%s with %s and %s allows %s, expected %s", test.source, test.engine.Name(), test.definition, allowed, test.allowed)
+    }
+  }
+}
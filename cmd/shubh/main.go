@@ -0,0 +1,286 @@
+package main
+
+import (
+  "flag"
+  "fmt"
+  "os"
+  "os/exec"
+  "time"
+
+  "shubhcron-pandit/pandit"
+)
+
+var policyFlag = flag.String("policy", os.Getenv("SHUBH_POLICY"), "strict, include-chal, or a list like day:amrit,labh|night:shubh")
+var cityFlag = flag.String("city", os.Getenv("CITY"), "city name to take the location and timezone from")
+var engineFlag = flag.String("engine", os.Getenv("SHUBH_ENGINE"), "kelvins, noaa, or table with SUN_TABLE set")
+var limbFlag = flag.String("limb", os.Getenv("SUNRISE_LIMB"), "upper or centre, the part of the sun that marks sunrise")
+var refractionFlag = flag.String("refraction", os.Getenv("SUNRISE_REFRACTION"), "standard or none")
+var elevationFlag = flag.String("elevation", os.Getenv("ELEVATION"), "observer elevation in metres")
+var polarFlag = flag.String("polar", os.Getenv("SHUBH_POLAR"), "none, clamp or civil, for days without sunrise or sunset")
+var rahuKaalFlag = flag.String("rahukaal", getEnv("SHUBH_RAHUKAAL", RAHU_KAAL_IGNORE), "ignore, refuse or wait, what to do during rahu kaal")
+var muhuratFlag = flag.String("muhurat", os.Getenv("SHUBH_MUHURAT"), "abhijit, brahma, pratah-sandhya, madhyahna-sandhya or sayam-sandhya, to run in it instead of a shubh chowgadhiya")
+
+// What to do during rahu kaal, on top of the policy
+const (
+  RAHU_KAAL_IGNORE = "ignore"
+  RAHU_KAAL_REFUSE = "refuse"
+  RAHU_KAAL_WAIT   = "wait"
+)
+
+// Times are worked out in the city's timezone when one is given
+var timezone = time.Local
+
+func printHelp() {
+  fmt.Println("Usage: shubh [--policy strict] [--city Mumbai] command [args...]")
+  fmt.Println("  Runs the command only if the time is auspicious")
+  fmt.Println("  Exits with status 1 otherwise")
+  fmt.Println("  Set SHUBH_WAIT environment variable to wait and run the command instead")
+  fmt.Println("  Set LATITUDE and LONGITUDE environment variables to change the location")
+  fmt.Println("  Set CITY environment variable to use a city from the gazetteer instead")
+  fmt.Println("  Set SHUBH_POLICY environment variable to change the default policy")
+  fmt.Println("  Set SHUBH_POLAR environment variable to handle polar days by default")
+  fmt.Println("  Set SHUBH_RAHUKAAL environment variable to refuse or wait during rahu kaal by default")
+  fmt.Println("  Set SHUBH_MUHURAT environment variable to run during a muhurat like brahma instead")
+  fmt.Println("  Set SHUBH_ENGINE environment variable to change the sunrise engine")
+  fmt.Println("  Set SUN_TABLE environment variable to a CSV file for the table engine")
+  fmt.Println("  Set SUNRISE_LIMB, SUNRISE_REFRACTION and ELEVATION to change what counts as sunrise")
+  fmt.Println("  Set DEBUG environment variable for debugging")
+  fmt.Println("Flags:")
+  flag.PrintDefaults()
+}
+
+/**
+ * Goes to stderr so that it does not mix
+ * with the output of the command
+ */
+func printStatus(now time.Time, period pandit.Period, window pandit.Window, shubh bool) {
+  remaining := period.End.Sub(now).Round(time.Second)
+  status := "not shubh"
+  if shubh {
+    status = "shubh until " + window.End.Format("15:04:05")
+  }
+  // A kaal spans the whole chowgadhiya it falls on
+  if period.Kaal != pandit.NoKaal {
+    status += ", " + period.Kaal.String() + " until " + period.End.Format("15:04:05")
+  }
+  if period.Fallback != pandit.FallbackNone {
+    status += ", polar fallback: " + period.Fallback.String()
+  }
+  fmt.Fprintf(os.Stderr, "%s (%s %s) from %s to %s (%s left), %s\n",
+    period.Chowgadhiya, period.Vaar, period.Phase, period.Start.Format("15:04:05"), period.End.Format("15:04:05"), remaining, status)
+}
+
+/**
+ * Runs the command if the time is Shubh, or in abhijit
+ * muhurat when the policy allows, and exits if it was ran.
+ * During rahu kaal it refuses, or waits it out and looks again
+ */
+func runCommand(calculator *pandit.Calculator, args []string) {
+  now := time.Now().In(timezone)
+
+  period, err := calculator.Chowgadhiya(now)
+  if err != nil {
+    fmt.Println("error in calculating chowgadhiya:", err)
+    os.Exit(255)
+  }
+  window, shubh, err := calculator.CurrentWindow(now)
+  if err != nil {
+    fmt.Println("error in calculating chowgadhiya:", err)
+    os.Exit(255)
+  }
+  printStatus(now, period, window, shubh)
+
+  if period.Kaal == pandit.RahuKaal && *rahuKaalFlag == RAHU_KAAL_WAIT {
+    pandit.Debug("Waiting for rahu kaal to end at", period.End)
+    time.Sleep(time.Until(period.End))
+    runCommand(calculator, args)
+    return
+  }
+
+  if shubh {
+    execute(args)
+  }
+}
+
+/**
+ * Runs the command if the time is within the muhurat,
+ * whatever the chowgadhiya, and exits if it was ran
+ */
+func runInMuhurat(calculator *pandit.Calculator, muhurat pandit.MuhuratType, args []string) {
+  now := time.Now().In(timezone)
+
+  period, err := calculator.NextMuhurat(muhurat, now)
+  if err != nil {
+    fmt.Println("error in calculating muhurat:", err)
+    os.Exit(255)
+  }
+  status := "starts in " + period.Start.Sub(now).Round(time.Second).String()
+  if period.Contains(now) {
+    status = period.End.Sub(now).Round(time.Second).String() + " left"
+  }
+  fmt.Fprintf(os.Stderr, "%s (%s) from %s to %s, %s\n",
+    period.Muhurat, period.Vaar, period.Start.Format("15:04:05"), period.End.Format("15:04:05"), status)
+
+  if period.Contains(now) {
+    execute(args)
+  }
+}
+
+/**
+ * Runs the command and exits with 0, or with 255 if it failed
+ */
+func execute(args []string) {
+  cmd := exec.Command(args[0], args[1:]...)
+  cmd.Stdout = os.Stdout
+  cmd.Stderr = os.Stderr
+  err := cmd.Run()
+  if err == nil {
+    os.Exit(0)
+  } else {
+    fmt.Println("error in executing command. Command:", args)
+    os.Exit(255)
+  }
+}
+
+func parseDefinition() (pandit.SunriseDefinition, error) {
+  var definition pandit.SunriseDefinition
+  var err error
+  if definition.Limb, err = pandit.ParseLimb(*limbFlag); err != nil {
+    return definition, err
+  }
+  if definition.Refraction, err = pandit.ParseRefraction(*refractionFlag); err != nil {
+    return definition, err
+  }
+  definition.Elevation, err = pandit.ParseElevation(*elevationFlag)
+  return definition, err
+}
+
+func getEnv(key, fallback string) string {
+  if value, ok := os.LookupEnv(key); ok {
+    return value
+  }
+  return fallback
+}
+
+func main() {
+  flag.Usage = printHelp
+  // Parsing stops at the first non-flag argument,
+  // so flags meant for the command are left alone
+  flag.Parse()
+
+  args := flag.Args()
+  if len(args) < 1 {
+    printHelp()
+    os.Exit(0)
+  }
+
+  location, err := pandit.LocationFromEnv()
+  if err != nil {
+    fmt.Println(err)
+    os.Exit(255)
+  }
+  if *cityFlag != "" {
+    city, err := pandit.LookupCity(*cityFlag)
+    if err != nil {
+      fmt.Println(err)
+      os.Exit(255)
+    }
+    location = city.Location()
+    timezone, err = city.TimeLocation()
+    if err != nil {
+      fmt.Println(err)
+      os.Exit(255)
+    }
+  }
+
+  location.Timezone = timezone
+
+  policy, err := pandit.ParsePolicy(*policyFlag)
+  if err != nil {
+    fmt.Println(err)
+    os.Exit(255)
+  }
+
+  calculator, err := pandit.NewCalculator(location, policy)
+  if err != nil {
+    fmt.Println(err)
+    os.Exit(255)
+  }
+  calculator.Polar, err = pandit.ParsePolarFallback(*polarFlag)
+  if err != nil {
+    fmt.Println(err)
+    os.Exit(255)
+  }
+  if err := pandit.RegisterEnginesFromEnv(); err != nil {
+    fmt.Println(err)
+    os.Exit(255)
+  }
+  calculator.Engine, err = pandit.ParseEngine(*engineFlag)
+  if err != nil {
+    fmt.Println(err)
+    os.Exit(255)
+  }
+  calculator.Definition, err = parseDefinition()
+  if err != nil {
+    fmt.Println(err)
+    os.Exit(255)
+  }
+  // Ignoring leaves it to the policy, which only avoids rahu kaal
+  // when it says so, refusing and waiting avoid it either way,
+  // abhijit muhurat or not
+  switch *rahuKaalFlag {
+  case RAHU_KAAL_IGNORE:
+  case RAHU_KAAL_REFUSE, RAHU_KAAL_WAIT:
+    calculator.Policy = calculator.Policy.RefusingKaal(pandit.RahuKaal)
+  default:
+    fmt.Printf("invalid rahu kaal mode %q, expected ignore, refuse or wait\n", *rahuKaalFlag)
+    os.Exit(255)
+  }
+  // The same few days get looked up over and over, more so in wait mode
+  calculator.Cache = pandit.NewEphemerisCache(pandit.DEFAULT_CACHE_SIZE)
+  pandit.Debug("Using policy", calculator.Policy)
+
+  _, wait := os.LookupEnv("SHUBH_WAIT")
+
+  if *muhuratFlag != "" {
+    muhurat, err := pandit.ParseMuhurat(*muhuratFlag)
+    if err != nil {
+      fmt.Println(err)
+      os.Exit(255)
+    }
+    runInMuhurat(calculator, muhurat, args)
+    if wait {
+      pandit.Debug("Running in wait mode")
+      for {
+        next, err := calculator.NextMuhurat(muhurat, time.Now().In(timezone))
+        if err != nil {
+          fmt.Println("error in calculating muhurat:", err)
+          os.Exit(255)
+        }
+        pandit.Debug("Waiting for", next.Muhurat, "at", next.Start)
+        time.Sleep(time.Until(next.Start))
+        runInMuhurat(calculator, muhurat, args)
+      }
+    }
+    os.Exit(1)
+  }
+
+  runCommand(calculator, args)
+  if wait {
+    pandit.Debug("Running in wait mode")
+    for {
+      // Sleep straight through to the start of the next
+      // shubh period instead of polling
+      now := time.Now().In(timezone)
+      next, err := calculator.NextShubh(now)
+      if err != nil {
+        fmt.Println("error in calculating chowgadhiya:", err)
+        os.Exit(255)
+      }
+      pandit.Debug("Waiting for", next.Chowgadhiya, "at", next.Start)
+      time.Sleep(time.Until(next.Start))
+      runCommand(calculator, args)
+    }
+  }
+  os.Exit(1)
+}
@@ -0,0 +1,164 @@
+package pandit
+
+import (
+  "container/list"
+  "sync"
+  "time"
+)
+
+const DEFAULT_CACHE_SIZE = 4096
+
+// How many days ahead warming and refreshing fill in
+const CACHE_WARM_DAYS = 7
+
+// How long before local midnight the next days get refreshed
+const CACHE_REFRESH_LEAD = 10 * time.Minute
+
+/**
+ * EphemerisCache keeps recent sun times, least recently used
+ * out first. It is safe to share between calculators and
+ * goroutines, the server gives every request the same one
+ */
+type EphemerisCache struct {
+  mutex    sync.Mutex
+  capacity int
+  entries  map[ephemerisKey]*list.Element
+  // Most recently used at the front
+  order    *list.List
+  hits     uint64
+  misses   uint64
+}
+
+/**
+ * Everything the sun times depend on. The UTC offset at local
+ * noon pins down the day's instants together with the date,
+ * whichever zone it came from
+ */
+type ephemerisKey struct {
+  engine     string
+  date       string
+  offset     int
+  latitude   float64
+  longitude  float64
+  definition SunriseDefinition
+}
+
+type ephemerisEntry struct {
+  key   ephemerisKey
+  times SunTimes
+  // Only ErrNoSunrise is kept, other errors are not cached
+  err   error
+}
+
+type CacheStats struct {
+  Hits     uint64
+  Misses   uint64
+  Size     int
+  Capacity int
+}
+
+func NewEphemerisCache(capacity int) *EphemerisCache {
+  if capacity < 1 {
+    capacity = DEFAULT_CACHE_SIZE
+  }
+  return &EphemerisCache{
+    capacity: capacity,
+    entries:  map[ephemerisKey]*list.Element{},
+    order:    list.New(),
+  }
+}
+
+func newEphemerisKey(engine SunCalculator, t time.Time, latitude float64, longitude float64, definition SunriseDefinition) ephemerisKey {
+  _, offset := time.Date(t.Year(), t.Month(), t.Day(), 12, 0, 0, 0, t.Location()).Zone()
+  return ephemerisKey{engine.Name(), t.Format("2006-01-02"), offset, latitude, longitude, definition}
+}
+
+/**
+ * Returns the cached sun times for key, or works them out
+ * with compute and remembers them
+ */
+func (cache *EphemerisCache) get(key ephemerisKey, compute func() (SunTimes, error)) (SunTimes, error) {
+  cache.mutex.Lock()
+  if element, ok := cache.entries[key]; ok {
+    cache.order.MoveToFront(element)
+    cache.hits++
+    entry := element.Value.(*ephemerisEntry)
+    cache.mutex.Unlock()
+    return entry.times, entry.err
+  }
+  cache.misses++
+  cache.mutex.Unlock()
+
+  // Worked out without the lock held, two goroutines missing
+  // on the same key at once both compute it
+  times, err := compute()
+  if err != nil && Cause(err) != ErrNoSunrise {
+    return times, err
+  }
+
+  cache.mutex.Lock()
+  defer cache.mutex.Unlock()
+  if element, ok := cache.entries[key]; ok {
+    cache.order.MoveToFront(element)
+    return times, err
+  }
+  cache.entries[key] = cache.order.PushFront(&ephemerisEntry{key, times, err})
+  for cache.order.Len() > cache.capacity {
+    oldest := cache.order.Back()
+    cache.order.Remove(oldest)
+    delete(cache.entries, oldest.Value.(*ephemerisEntry).key)
+  }
+  return times, err
+}
+
+func (cache *EphemerisCache) Stats() CacheStats {
+  cache.mutex.Lock()
+  defer cache.mutex.Unlock()
+  return CacheStats{cache.hits, cache.misses, cache.order.Len(), cache.capacity}
+}
+
+/**
+ * Fills the cache with the sun times from the day before from
+ * up to days after it. Meant to run in the background
+ */
+func (c *Calculator) WarmCache(from time.Time, days int) {
+  from = midnight(from.In(c.timezone(from)))
+  for i := -1; i <= days; i++ {
+    date := from.AddDate(0, 0, i)
+    if _, err := c.SunTimes(date); err != nil && Cause(err) != ErrNoSunrise {
+      debug("Warming cache failed on", date.Format("2006-01-02"), err)
+    }
+  }
+  debug("Warmed cache from", from.Format("2006-01-02"), "for", days, "days")
+}
+
+/**
+ * Warms the cache shortly before every local midnight, so the day
+ * ahead is ready before anyone asks for it. Runs until stop is
+ * closed, meant to be started in its own goroutine
+ */
+func (c *Calculator) RefreshCache(days int, stop <-chan struct{}) {
+  for {
+    now := time.Now()
+    next := c.nextRefresh(now)
+    select {
+    case <-stop:
+      return
+    case <-time.After(next.Sub(now)):
+      c.WarmCache(next.Add(CACHE_REFRESH_LEAD), days)
+    }
+  }
+}
+
+/**
+ * CACHE_REFRESH_LEAD before the first local midnight that is
+ * still more than that away from now
+ */
+func (c *Calculator) nextRefresh(now time.Time) time.Time {
+  now = now.In(c.timezone(now))
+  next := addDays(now, 1).Add(-CACHE_REFRESH_LEAD)
+  if !next.After(now) {
+    next = addDays(now, 2).Add(-CACHE_REFRESH_LEAD)
+  }
+  return next
+}
@@ -0,0 +1,151 @@
+package pandit
+
+import (
+  "errors"
+  "testing"
+  "time"
+)
+
+func cacheKey(date string) ephemerisKey {
+  return ephemerisKey{engine: "test", date: date}
+}
+
+/**
+ * A compute that counts its calls and gives back a sunrise
+ * on the key's date, or err when set
+ */
+type countingCompute struct {
+  calls int
+  err   error
+}
+
+func (c *countingCompute) on(date string) func() (SunTimes, error) {
+  return func() (SunTimes, error) {
+    c.calls++
+    sunrise, _ := time.Parse("2006-01-02", date)
+    return SunTimes{Sunrise: sunrise}, c.err
+  }
+}
+
+func TestCacheEviction(t *testing.T) {
+  cache := NewEphemerisCache(2)
+  compute := &countingCompute{}
+  get := func(date string) {
+    times, err := cache.get(cacheKey(date), compute.on(date))
+    if err != nil || times.Sunrise.Format("2006-01-02") != date {
+      t.Fatalf("%s: got %s, %v", date, times.Sunrise, err)
+    }
+  }
+
+  get("2026-10-18")
+  get("2026-10-19")
+  // 18 is now the most recently used, so 19 goes when 20 comes in
+  get("2026-10-18")
+  get("2026-10-20")
+  if compute.calls != 3 {
+    t.Errorf("%d computations for 3 dates", compute.calls)
+  }
+  get("2026-10-18")
+  if compute.calls != 3 {
+    t.Errorf("2026-10-18 was evicted, expected 2026-10-19 to be")
+  }
+  get("2026-10-19")
+  if compute.calls != 4 {
+    t.Errorf("2026-10-19 was kept past the capacity")
+  }
+
+  want := CacheStats{Hits: 2, Misses: 4, Size: 2, Capacity: 2}
+  if stats := cache.Stats(); stats != want {
+    t.Errorf("stats %+v, expected %+v", stats, want)
+  }
+}
+
+func TestCacheErrors(t *testing.T) {
+  cache := NewEphemerisCache(DEFAULT_CACHE_SIZE)
+  tests := []struct {
+    err    error
+    cached bool
+  }{
+    // A day without a sunrise stays that way, anything else may not
+    {newError(ErrNoSunrise, "polar night"), true},
+    {newError(ErrDateOutOfRange, "1899"), false},
+    {errors.New("engine failed"), false},
+  }
+  for i, test := range tests {
+    compute := &countingCompute{err: test.err}
+    key := cacheKey(time.Date(2026, 10, 18+i, 0, 0, 0, 0, time.UTC).Format("2006-01-02"))
+    for j := 0; j < 2; j++ {
+      if _, err := cache.get(key, compute.on(key.date)); err != test.err {
+        t.Errorf("%v: got %v back", test.err, err)
+      }
+    }
+    if calls := map[bool]int{true: 1, false: 2}[test.cached]; compute.calls != calls {
+      t.Errorf("%v: computed %d times, expected %d", test.err, compute.calls, calls)
+    }
+  }
+  if size := cache.Stats().Size; size != 1 {
+    t.Errorf("%d entries, expected only the one without a sunrise", size)
+  }
+}
+
+func TestCacheNextRefresh(t *testing.T) {
+  london, err := time.LoadLocation("Europe/London")
+  if err != nil {
+    t.Fatal(err)
+  }
+  calculator, err := NewCalculator(Location{Latitude: 51.5, Longitude: -0.13, Timezone: london}, DefaultPolicy)
+  if err != nil {
+    t.Fatal(err)
+  }
+  tests := []struct {
+    now  string
+    next string
+  }{
+    {"2026-10-18T12:00:00+01:00", "2026-10-18T23:50:00+01:00"},
+    // Already inside the lead, so the midnight after
+    {"2026-10-18T23:55:00+01:00", "2026-10-19T23:50:00+01:00"},
+    // The clocks go back in the night of 10-25, that midnight is in GMT
+    {"2026-10-25T12:00:00Z", "2026-10-25T23:50:00Z"},
+    // Asked in another zone, the calculator's midnight counts
+    {"2026-10-18T20:00:00-04:00", "2026-10-19T23:50:00+01:00"},
+  }
+  for _, test := range tests {
+    now, err := time.Parse(time.RFC3339, test.now)
+    if err != nil {
+      t.Fatal(err)
+    }
+    want, err := time.Parse(time.RFC3339, test.next)
+    if err != nil {
+      t.Fatal(err)
+    }
+    if next := calculator.nextRefresh(now); !next.Equal(want) {
+      t.Errorf("at %s the next refresh is %s, expected %s", test.now, next, test.next)
+    }
+  }
+}
+
+func TestCacheWarmAndRefreshStop(t *testing.T) {
+  calculator, err := NewCalculator(Location{Latitude: 26.7880, Longitude: 82.1986, Timezone: time.FixedZone("IST", 19800)}, DefaultPolicy)
+  if err != nil {
+    t.Fatal(err)
+  }
+  calculator.Cache = NewEphemerisCache(DEFAULT_CACHE_SIZE)
+  calculator.WarmCache(time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC), CACHE_WARM_DAYS)
+  // The day before and CACHE_WARM_DAYS after
+  if stats := calculator.Cache.Stats(); stats.Size != CACHE_WARM_DAYS+2 || stats.Hits != 0 {
+    t.Errorf("warming gives %+v", stats)
+  }
+
+  stop := make(chan struct{})
+  done := make(chan struct{})
+  go func() {
+    calculator.RefreshCache(CACHE_WARM_DAYS, stop)
+    close(done)
+  }()
+  close(stop)
+  select {
+  case <-done:
+  case <-time.After(time.Second):
+    t.Error("RefreshCache did not return once stopped")
+  }
+}
@@ -0,0 +1,253 @@
+package pandit
+
+import (
+  "os"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// Ultimate default coordinates
+const DEFAULT_LATITUDE  string = "26.7880"
+const DEFAULT_LONGITUDE string = "82.1986"
+
+type Location struct {
+  Latitude  float64
+  Longitude float64
+  // Decides what a calendar date is and which UTC offset
+  // applies on it. When nil, the location of the time
+  // passed in to each call is used
+  Timezone  *time.Location
+}
+
+/**
+ * Calculator holds everything needed to answer
+ * questions about a given instant: where we are
+ * and which chowgadhiyas we consider shubh
+ */
+type Calculator struct {
+  Location   Location
+  Policy     Policy
+  // What to do on days the sun does not rise or set
+  Polar      PolarFallback
+  // Where sunrise and sunset come from, DefaultEngine when nil
+  Engine     SunCalculator
+  // When the sun counts as risen and set
+  Definition SunriseDefinition
+  // Shared sun times, nothing is cached when nil
+  Cache      *EphemerisCache
+}
+
+func NewCalculator(location Location, policy Policy) (*Calculator, error) {
+  if err := location.Validate(); err != nil {
+    return nil, err
+  }
+  return &Calculator{Location: location, Policy: policy, Engine: DefaultEngine}, nil
+}
+
+func (l Location) Validate() error {
+  // NaN fails both comparisons, so check the other way round
+  if !(l.Latitude >= -90 && l.Latitude <= 90) {
+    return newError(ErrInvalidLocation, "latitude %v is out of range -90..90", l.Latitude)
+  }
+  if !(l.Longitude >= -180 && l.Longitude <= 180) {
+    return newError(ErrInvalidLocation, "longitude %v is out of range -180..180", l.Longitude)
+  }
+  return nil
+}
+
+/**
+ * Parses and validates a latitude and longitude
+ * given as decimal degrees
+ */
+func ParseLocation(latitude string, longitude string) (Location, error) {
+  lat, err := strconv.ParseFloat(strings.TrimSpace(latitude), 64)
+  if err != nil {
+    return Location{}, newError(ErrInvalidLocation, "latitude %q is not a number", latitude)
+  }
+  lon, err := strconv.ParseFloat(strings.TrimSpace(longitude), 64)
+  if err != nil {
+    return Location{}, newError(ErrInvalidLocation, "longitude %q is not a number", longitude)
+  }
+  location := Location{Latitude: lat, Longitude: lon}
+  return location, location.Validate()
+}
+
+/**
+ * Reads LATITUDE and LONGITUDE from the environment,
+ * falling back to the default coordinates.
+ * Meant to be called once at startup
+ */
+func LocationFromEnv() (Location, error) {
+  return ParseLocation(getEnv("LATITUDE", DEFAULT_LATITUDE), getEnv("LONGITUDE", DEFAULT_LONGITUDE))
+}
+
+func getEnv(key, fallback string) string {
+  if value, ok := os.LookupEnv(key); ok {
+    return value
+  }
+  return fallback
+}
+
+/**
+ * Returns the timezone t should be looked at in
+ */
+func (c *Calculator) timezone(t time.Time) *time.Location {
+  if c.Location.Timezone != nil {
+    return c.Location.Timezone
+  }
+  return t.Location()
+}
+
+func (c *Calculator) engine() SunCalculator {
+  if c.Engine != nil {
+    return c.Engine
+  }
+  return DefaultEngine
+}
+
+/**
+ * Returns the sunrise and sunset on the calendar date of t,
+ * as instants in the calculator's timezone
+ */
+func (c *Calculator) SunriseSunset(t time.Time) (time.Time, time.Time, error) {
+  times, _, err := c.sunriseSunset(t)
+  return times.Sunrise, times.Sunset, err
+}
+
+/**
+ * Same as SunTimes, but applies the polar fallback on days
+ * the sun does not rise or set and reports which one was used
+ */
+func (c *Calculator) sunriseSunset(t time.Time) (SunTimes, PolarFallback, error) {
+  times, err := c.SunTimes(t)
+  if Cause(err) != ErrNoSunrise {
+    return times, FallbackNone, err
+  }
+
+  switch c.Polar {
+  case FallbackClamp:
+    times, err = c.clampedSunTimes(t)
+  case FallbackCivil:
+    times = civilSunTimes(t.In(c.timezone(t)))
+    err = nil
+  default:
+    return times, FallbackNone, err
+  }
+  debug("Applied polar fallback", c.Polar, "on", t.Format("2006-01-02"))
+  return times, c.Polar, err
+}
+
+/**
+ * Returns the sunrise, sunset and solar noon on the calendar date
+ * of t from the calculator's engine, without any polar fallback
+ */
+func (c *Calculator) SunTimes(t time.Time) (SunTimes, error) {
+  return c.sunTimesAt(t, c.Location.Latitude)
+}
+
+func (c *Calculator) sunTimesAt(t time.Time, latitude float64) (SunTimes, error) {
+  loc := c.timezone(t)
+  t = t.In(loc)
+  engine := c.engine()
+  compute := func() (SunTimes, error) {
+    return engine.SunTimes(t, latitude, c.Location.Longitude, c.Definition)
+  }
+
+  var times SunTimes
+  var err error
+  if c.Cache != nil {
+    times, err = c.Cache.get(newEphemerisKey(engine, t, latitude, c.Location.Longitude, c.Definition), compute)
+  } else {
+    times, err = compute()
+  }
+  if err != nil {
+    return SunTimes{}, err
+  }
+  return SunTimes{
+    Sunrise:   times.Sunrise.In(loc),
+    Sunset:    times.Sunset.In(loc),
+    SolarNoon: times.SolarNoon.In(loc),
+  }, nil
+}
+
+func midnight(t time.Time) time.Time {
+  return startOfDay(t.Year(), t.Month(), t.Day(), t.Location())
+}
+
+// Midnight days calendar days after the date of t, whatever DST does in between
+func addDays(t time.Time, days int) time.Time {
+  return startOfDay(t.Year(), t.Month(), t.Day()+days, t.Location())
+}
+
+/**
+ * The first instant of the date. Where DST starts at midnight
+ * (Sao Paulo until 2019) there is no 00:00, and time.Date puts it
+ * in the day before, so go on to when the clocks go forward
+ */
+func startOfDay(year int, month time.Month, day int, loc *time.Location) time.Time {
+  start := time.Date(year, month, day, 0, 0, 0, 0, loc)
+  noon := time.Date(year, month, day, 12, 0, 0, 0, loc)
+  for start.Day() != noon.Day() && start.Before(noon) {
+    start = start.Add(time.Minute)
+  }
+  return start
+}
+
+/**
+ * Takes time and returns the correct Chowgadhiya
+ */
+func (c *Calculator) Chowgadhiya(t time.Time) (Period, error) {
+  day, err := c.VedicDay(t)
+  if err != nil {
+    return Period{}, err
+  }
+
+  debug("Vaar:", day.Vaar)
+  debug("Current time:", t)
+
+  // Same periods the schedule and the iterator hand out,
+  // so a lookup never disagrees with them at a boundary
+  period, ok := periodAt(day.Periods(), t)
+  if !ok {
+    return Period{}, newError(ErrInconsistentVedicDay, "%v does not fall between sunrise %v and next sunrise %v", t, day.Sunrise, day.NextSunrise)
+  }
+  debug("phase:", period.Phase)
+  return period, nil
+}
+
+/**
+ * returns whether t is an auspicious time or not,
+ * including abhijit muhurat when the policy says so
+ * and t is not in a kaal it refuses
+ */
+func (c *Calculator) IsShubh(t time.Time) (bool, error) {
+  period, err := c.Chowgadhiya(t)
+  if err != nil {
+    return false, err
+  }
+  debug("Picked Chowgadhiya", period.Chowgadhiya)
+  if c.Policy.IsShubh(period) {
+    return true, nil
+  }
+  if c.Policy.Refuses(period.Kaal) {
+    return false, nil
+  }
+  return c.InAbhijit(t)
+}
+
+/**
+ * Returns whether t falls in abhijit muhurat and
+ * the policy counts it as shubh
+ */
+func (c *Calculator) InAbhijit(t time.Time) (bool, error) {
+  if !c.Policy.Abhijit {
+    return false, nil
+  }
+  day, err := c.VedicDay(t)
+  if err != nil {
+    return false, err
+  }
+  abhijit, ok := day.Abhijit()
+  return ok && abhijit.Contains(t), nil
+}
@@ -0,0 +1,63 @@
+package pandit
+
+import (
+  "testing"
+  "time"
+)
+
+/**
+ * A calculator for the gazetteer city in its own timezone,
+ * with the default policy and the engine given
+ */
+func cityCalculator(t testing.TB, name string, engine SunCalculator) (*Calculator, *time.Location) {
+  city, err := LookupCity(name)
+  if err != nil {
+    t.Fatal(err)
+  }
+  timezone, err := city.TimeLocation()
+  if err != nil {
+    t.Fatal(err)
+  }
+  location := city.Location()
+  location.Timezone = timezone
+  calculator, err := NewCalculator(location, DefaultPolicy)
+  if err != nil {
+    t.Fatal(err)
+  }
+  calculator.Engine = engine
+  return calculator, timezone
+}
+
+/**
+ * Times whole lookups at the same instant, sun times and all,
+ * BENCH_LOCATION and BENCH_DATE are in kelvins_test.go
+ */
+func benchmarkChowgadhiya(b *testing.B, engine SunCalculator, cache *EphemerisCache) {
+  calculator, err := NewCalculator(BENCH_LOCATION, DefaultPolicy)
+  if err != nil {
+    b.Fatal(err)
+  }
+  calculator.Engine = engine
+  calculator.Cache = cache
+  now := BENCH_DATE.Add(9 * time.Hour)
+
+  b.ReportAllocs()
+  b.ResetTimer()
+  for i := 0; i < b.N; i++ {
+    if _, err := calculator.Chowgadhiya(now); err != nil {
+      b.Fatal(err)
+    }
+  }
+}
+
+func BenchmarkChowgadhiyaKelvins(b *testing.B) {
+  benchmarkChowgadhiya(b, KelvinsSunCalculator{}, nil)
+}
+
+func BenchmarkChowgadhiyaNOAA(b *testing.B) {
+  benchmarkChowgadhiya(b, NOAASunCalculator{}, nil)
+}
+
+func BenchmarkChowgadhiyaCached(b *testing.B) {
+  benchmarkChowgadhiya(b, DefaultEngine, NewEphemerisCache(DEFAULT_CACHE_SIZE))
+}
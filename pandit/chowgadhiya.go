@@ -0,0 +1,115 @@
+package pandit
+
+import (
+  "fmt"
+  "strings"
+  "time"
+)
+
+type Chowgadhiya int
+type Phase int
+
+const (
+  Day Phase = iota
+  Night
+)
+
+const (
+  Chal Chowgadhiya = iota
+  Amrit
+  Kaal
+  Labh
+  Rog
+  Shubh
+  Udveg
+)
+
+// https://hinduism.stackexchange.com/questions/26242/how-is-the-first-choghadiya-decided
+// Golang does not allow constant maps, but a literal map is close enough
+var CHOWGADHIYA_LIST = map[Phase]map[time.Weekday][]Chowgadhiya{
+  Day: map[time.Weekday][]Chowgadhiya{
+    time.Sunday:    []Chowgadhiya{Udveg, Chal, Labh, Amrit, Kaal, Shubh, Rog, Udveg},
+    time.Monday:    []Chowgadhiya{Amrit, Kaal, Shubh, Rog, Udveg, Chal, Labh, Amrit},
+    time.Tuesday:   []Chowgadhiya{Rog, Udveg, Chal, Labh, Amrit, Kaal, Shubh, Rog},
+    time.Wednesday: []Chowgadhiya{Labh, Amrit, Kaal, Shubh, Rog, Udveg, Chal, Labh},
+    time.Thursday:  []Chowgadhiya{Shubh, Rog, Udveg, Chal, Labh, Amrit, Kaal, Shubh},
+    time.Friday:    []Chowgadhiya{Chal, Labh, Amrit, Kaal, Shubh, Rog, Udveg, Chal},
+    time.Saturday:  []Chowgadhiya{Kaal, Shubh, Rog, Udveg, Chal, Labh, Amrit, Kaal},
+  },
+  Night: map[time.Weekday][]Chowgadhiya{
+    time.Sunday:    []Chowgadhiya{Shubh, Amrit, Chal, Rog, Kaal, Labh, Udveg, Shubh},
+    time.Monday:    []Chowgadhiya{Chal, Rog, Kaal, Labh, Udveg, Shubh, Amrit, Chal},
+    time.Tuesday:   []Chowgadhiya{Kaal, Labh, Udveg, Shubh, Amrit, Chal, Rog, Kaal},
+    time.Wednesday: []Chowgadhiya{Udveg, Shubh, Amrit, Chal, Rog, Kaal, Labh, Udveg},
+    time.Thursday:  []Chowgadhiya{Amrit, Chal, Rog, Kaal, Labh, Udveg, Shubh, Amrit},
+    time.Friday:    []Chowgadhiya{Rog, Kaal, Labh, Udveg, Shubh, Amrit, Chal, Rog},
+    time.Saturday:  []Chowgadhiya{Labh, Udveg, Shubh, Amrit, Chal, Rog, Kaal, Labh},
+  },
+}
+
+var chowgadhiyaNames = map[Chowgadhiya]string{
+  Chal:  "chal",
+  Amrit: "amrit",
+  Kaal:  "kaal",
+  Labh:  "labh",
+  Rog:   "rog",
+  Shubh: "shubh",
+  Udveg: "udveg",
+}
+
+var phaseNames = map[Phase]string{
+  Day:   "day",
+  Night: "night",
+}
+
+/**
+ * Returns the lowercase name used in API responses
+ */
+func (c Chowgadhiya) String() string {
+  if name, ok := chowgadhiyaNames[c]; ok {
+    return name
+  }
+  return "unknown"
+}
+
+func ParseChowgadhiya(name string) (Chowgadhiya, error) {
+  name = strings.ToLower(strings.TrimSpace(name))
+  for c, n := range chowgadhiyaNames {
+    if n == name {
+      return c, nil
+    }
+  }
+  return 0, fmt.Errorf("unknown chowgadhiya %q", name)
+}
+
+func (p Phase) String() string {
+  if name, ok := phaseNames[p]; ok {
+    return name
+  }
+  return "unknown"
+}
+
+/**
+ * Returns the list of Chowgadhiyas in order for the given
+ * weekday and phase. Go through VedicDay.List to get the
+ * weekday right between midnight and sunrise
+ */
+func ListFromWeekday(day time.Weekday, phase Phase) []Chowgadhiya {
+  return CHOWGADHIYA_LIST[phase][day]
+}
+
+// A single chowgadhiya with its boundaries
+type Period struct {
+  Chowgadhiya Chowgadhiya
+  Phase       Phase
+  // Of the vedic day, from the sunrise that started it
+  Vaar        Vaar
+  Start       time.Time
+  End         time.Time
+  // Set when the sun did not rise or set on this vedic day
+  // and its boundaries come from a polar fallback instead
+  Fallback    PolarFallback
+  // The kaal that takes the same eighth of the daytime,
+  // NoKaal at night and for most of the day
+  Kaal        KaalType
+}
@@ -0,0 +1,144 @@
+package pandit
+
+// Offline gazetteer, coordinates in decimal degrees and
+// elevation in metres above sea level
+var CITIES = []City{
+  // India
+  {"Mumbai", []string{"Bombay"}, "IN", 19.0760, 72.8777, 14, "Asia/Kolkata"},
+  {"Delhi", []string{"New Delhi"}, "IN", 28.6139, 77.2090, 216, "Asia/Kolkata"},
+  {"Bengaluru", []string{"Bangalore"}, "IN", 12.9716, 77.5946, 920, "Asia/Kolkata"},
+  {"Hyderabad", nil, "IN", 17.3850, 78.4867, 505, "Asia/Kolkata"},
+  {"Ahmedabad", []string{"Amdavad"}, "IN", 23.0225, 72.5714, 53, "Asia/Kolkata"},
+  {"Chennai", []string{"Madras"}, "IN", 13.0827, 80.2707, 6, "Asia/Kolkata"},
+  {"Kolkata", []string{"Calcutta"}, "IN", 22.5726, 88.3639, 9, "Asia/Kolkata"},
+  {"Pune", []string{"Poona"}, "IN", 18.5204, 73.8567, 560, "Asia/Kolkata"},
+  {"Jaipur", nil, "IN", 26.9124, 75.7873, 431, "Asia/Kolkata"},
+  {"Surat", nil, "IN", 21.1702, 72.8311, 13, "Asia/Kolkata"},
+  {"Lucknow", nil, "IN", 26.8467, 80.9462, 123, "Asia/Kolkata"},
+  {"Kanpur", nil, "IN", 26.4499, 80.3319, 126, "Asia/Kolkata"},
+  {"Nagpur", nil, "IN", 21.1458, 79.0882, 310, "Asia/Kolkata"},
+  {"Indore", nil, "IN", 22.7196, 75.8577, 553, "Asia/Kolkata"},
+  {"Thane", nil, "IN", 19.2183, 72.9781, 7, "Asia/Kolkata"},
+  {"Navi Mumbai", nil, "IN", 19.0330, 73.0297, 14, "Asia/Kolkata"},
+  {"Bhopal", nil, "IN", 23.2599, 77.4126, 527, "Asia/Kolkata"},
+  {"Visakhapatnam", []string{"Vizag"}, "IN", 17.6868, 83.2185, 45, "Asia/Kolkata"},
+  {"Patna", nil, "IN", 25.5941, 85.1376, 53, "Asia/Kolkata"},
+  {"Vadodara", []string{"Baroda"}, "IN", 22.3072, 73.1812, 35, "Asia/Kolkata"},
+  {"Ghaziabad", nil, "IN", 28.6692, 77.4538, 214, "Asia/Kolkata"},
+  {"Noida", nil, "IN", 28.5355, 77.3910, 200, "Asia/Kolkata"},
+  {"Gurugram", []string{"Gurgaon"}, "IN", 28.4595, 77.0266, 217, "Asia/Kolkata"},
+  {"Faridabad", nil, "IN", 28.4089, 77.3178, 198, "Asia/Kolkata"},
+  {"Meerut", nil, "IN", 28.9845, 77.7064, 219, "Asia/Kolkata"},
+  {"Ludhiana", nil, "IN", 30.9010, 75.8573, 244, "Asia/Kolkata"},
+  {"Amritsar", nil, "IN", 31.6340, 74.8723, 234, "Asia/Kolkata"},
+  {"Chandigarh", nil, "IN", 30.7333, 76.7794, 321, "Asia/Kolkata"},
+  {"Jammu", nil, "IN", 32.7266, 74.8570, 327, "Asia/Kolkata"},
+  {"Srinagar", nil, "IN", 34.0837, 74.7973, 1585, "Asia/Kolkata"},
+  {"Leh", nil, "IN", 34.1526, 77.5771, 3500, "Asia/Kolkata"},
+  {"Shimla", nil, "IN", 31.1048, 77.1734, 2206, "Asia/Kolkata"},
+  {"Dehradun", nil, "IN", 30.3165, 78.0322, 640, "Asia/Kolkata"},
+  {"Haridwar", nil, "IN", 29.9457, 78.1642, 314, "Asia/Kolkata"},
+  {"Rishikesh", nil, "IN", 30.0869, 78.2676, 372, "Asia/Kolkata"},
+  {"Agra", nil, "IN", 27.1767, 78.0081, 171, "Asia/Kolkata"},
+  {"Mathura", nil, "IN", 27.4924, 77.6737, 174, "Asia/Kolkata"},
+  {"Ayodhya", []string{"Faizabad"}, "IN", 26.7880, 82.1986, 93, "Asia/Kolkata"},
+  {"Varanasi", []string{"Banaras", "Benares", "Kashi"}, "IN", 25.3176, 82.9739, 80, "Asia/Kolkata"},
+  {"Prayagraj", []string{"Allahabad"}, "IN", 25.4358, 81.8463, 98, "Asia/Kolkata"},
+  {"Gwalior", nil, "IN", 26.2183, 78.1828, 211, "Asia/Kolkata"},
+  {"Jabalpur", nil, "IN", 23.1815, 79.9864, 412, "Asia/Kolkata"},
+  {"Ujjain", nil, "IN", 23.1765, 75.7885, 494, "Asia/Kolkata"},
+  {"Jodhpur", nil, "IN", 26.2389, 73.0243, 231, "Asia/Kolkata"},
+  {"Udaipur", nil, "IN", 24.5854, 73.7125, 598, "Asia/Kolkata"},
+  {"Kota", nil, "IN", 25.2138, 75.8648, 271, "Asia/Kolkata"},
+  {"Rajkot", nil, "IN", 22.3039, 70.8022, 128, "Asia/Kolkata"},
+  {"Nashik", []string{"Nasik"}, "IN", 19.9975, 73.7898, 700, "Asia/Kolkata"},
+  {"Aurangabad", []string{"Chhatrapati Sambhajinagar"}, "IN", 19.8762, 75.3433, 568, "Asia/Kolkata"},
+  {"Raipur", nil, "IN", 21.2514, 81.6296, 298, "Asia/Kolkata"},
+  {"Ranchi", nil, "IN", 23.3441, 85.3096, 651, "Asia/Kolkata"},
+  {"Bhubaneswar", nil, "IN", 20.2961, 85.8245, 45, "Asia/Kolkata"},
+  {"Guwahati", nil, "IN", 26.1445, 91.7362, 55, "Asia/Kolkata"},
+  {"Shillong", nil, "IN", 25.5788, 91.8933, 1525, "Asia/Kolkata"},
+  {"Gangtok", nil, "IN", 27.3389, 88.6065, 1650, "Asia/Kolkata"},
+  {"Darjeeling", nil, "IN", 27.0410, 88.2663, 2042, "Asia/Kolkata"},
+  {"Imphal", nil, "IN", 24.8170, 93.9368, 786, "Asia/Kolkata"},
+  {"Agartala", nil, "IN", 23.8315, 91.2868, 12, "Asia/Kolkata"},
+  {"Port Blair", []string{"Sri Vijaya Puram"}, "IN", 11.6234, 92.7265, 16, "Asia/Kolkata"},
+  {"Panaji", []string{"Panjim", "Goa"}, "IN", 15.4909, 73.8278, 7, "Asia/Kolkata"},
+  {"Mangaluru", []string{"Mangalore"}, "IN", 12.9141, 74.8560, 22, "Asia/Kolkata"},
+  {"Mysuru", []string{"Mysore"}, "IN", 12.2958, 76.6394, 763, "Asia/Kolkata"},
+  {"Hubballi", []string{"Hubli"}, "IN", 15.3647, 75.1240, 671, "Asia/Kolkata"},
+  {"Belagavi", []string{"Belgaum"}, "IN", 15.8497, 74.4977, 751, "Asia/Kolkata"},
+  {"Coimbatore", nil, "IN", 11.0168, 76.9558, 411, "Asia/Kolkata"},
+  {"Ooty", []string{"Udhagamandalam"}, "IN", 11.4102, 76.6950, 2240, "Asia/Kolkata"},
+  {"Madurai", nil, "IN", 9.9252, 78.1198, 134, "Asia/Kolkata"},
+  {"Tiruchirappalli", []string{"Trichy"}, "IN", 10.7905, 78.7047, 88, "Asia/Kolkata"},
+  {"Puducherry", []string{"Pondicherry"}, "IN", 11.9416, 79.8083, 3, "Asia/Kolkata"},
+  {"Tirupati", nil, "IN", 13.6288, 79.4192, 162, "Asia/Kolkata"},
+  {"Vijayawada", nil, "IN", 16.5062, 80.6480, 23, "Asia/Kolkata"},
+  {"Kochi", []string{"Cochin"}, "IN", 9.9312, 76.2673, 3, "Asia/Kolkata"},
+  {"Thiruvananthapuram", []string{"Trivandrum"}, "IN", 8.5241, 76.9366, 10, "Asia/Kolkata"},
+
+  // Neighbours and the rest of Asia
+  {"Kathmandu", nil, "NP", 27.7172, 85.3240, 1400, "Asia/Kathmandu"},
+  {"Dhaka", nil, "BD", 23.8103, 90.4125, 4, "Asia/Dhaka"},
+  {"Colombo", nil, "LK", 6.9271, 79.8612, 1, "Asia/Colombo"},
+  {"Karachi", nil, "PK", 24.8607, 67.0011, 8, "Asia/Karachi"},
+  {"Kabul", nil, "AF", 34.5553, 69.2075, 1791, "Asia/Kabul"},
+  {"Tehran", nil, "IR", 35.6892, 51.3890, 1190, "Asia/Tehran"},
+  {"Dubai", nil, "AE", 25.2048, 55.2708, 5, "Asia/Dubai"},
+  {"Abu Dhabi", nil, "AE", 24.4539, 54.3773, 27, "Asia/Dubai"},
+  {"Doha", nil, "QA", 25.2854, 51.5310, 10, "Asia/Qatar"},
+  {"Riyadh", nil, "SA", 24.7136, 46.6753, 612, "Asia/Riyadh"},
+  {"Singapore", nil, "SG", 1.3521, 103.8198, 15, "Asia/Singapore"},
+  {"Kuala Lumpur", nil, "MY", 3.1390, 101.6869, 56, "Asia/Kuala_Lumpur"},
+  {"Bangkok", nil, "TH", 13.7563, 100.5018, 2, "Asia/Bangkok"},
+  {"Jakarta", nil, "ID", -6.2088, 106.8456, 8, "Asia/Jakarta"},
+  {"Hong Kong", nil, "HK", 22.3193, 114.1694, 32, "Asia/Hong_Kong"},
+  {"Shanghai", nil, "CN", 31.2304, 121.4737, 4, "Asia/Shanghai"},
+  {"Tokyo", nil, "JP", 35.6762, 139.6503, 40, "Asia/Tokyo"},
+  {"Seoul", nil, "KR", 37.5665, 126.9780, 38, "Asia/Seoul"},
+
+  // Oceania
+  {"Sydney", nil, "AU", -33.8688, 151.2093, 58, "Australia/Sydney"},
+  {"Melbourne", nil, "AU", -37.8136, 144.9631, 31, "Australia/Melbourne"},
+  {"Adelaide", nil, "AU", -34.9285, 138.6007, 50, "Australia/Adelaide"},
+  {"Perth", nil, "AU", -31.9505, 115.8605, 31, "Australia/Perth"},
+  {"Auckland", nil, "NZ", -36.8485, 174.7633, 26, "Pacific/Auckland"},
+  {"Apia", nil, "WS", -13.8333, -171.7667, 2, "Pacific/Apia"},
+  {"Kiritimati", []string{"Christmas Island"}, "KI", 1.8721, -157.4278, 2, "Pacific/Kiritimati"},
+  {"Honolulu", nil, "US", 21.3069, -157.8583, 6, "Pacific/Honolulu"},
+
+  // Europe
+  {"London", nil, "GB", 51.5074, -0.1278, 11, "Europe/London"},
+  {"Paris", nil, "FR", 48.8566, 2.3522, 35, "Europe/Paris"},
+  {"Berlin", nil, "DE", 52.5200, 13.4050, 34, "Europe/Berlin"},
+  {"Frankfurt", nil, "DE", 50.1109, 8.6821, 112, "Europe/Berlin"},
+  {"Amsterdam", nil, "NL", 52.3676, 4.9041, -2, "Europe/Amsterdam"},
+  {"Zurich", nil, "CH", 47.3769, 8.5417, 408, "Europe/Zurich"},
+  {"Moscow", nil, "RU", 55.7558, 37.6173, 156, "Europe/Moscow"},
+  {"Stockholm", nil, "SE", 59.3293, 18.0686, 28, "Europe/Stockholm"},
+  {"Oslo", nil, "NO", 59.9139, 10.7522, 23, "Europe/Oslo"},
+  {"Helsinki", nil, "FI", 60.1699, 24.9384, 17, "Europe/Helsinki"},
+  {"Reykjavik", nil, "IS", 64.1466, -21.9426, 13, "Atlantic/Reykjavik"},
+  {"Tromso", []string{"Tromsø"}, "NO", 69.6492, 18.9553, 10, "Europe/Oslo"},
+  {"Longyearbyen", nil, "SJ", 78.2232, 15.6267, 10, "Arctic/Longyearbyen"},
+
+  // Africa
+  {"Cairo", nil, "EG", 30.0444, 31.2357, 23, "Africa/Cairo"},
+  {"Lagos", nil, "NG", 6.5244, 3.3792, 41, "Africa/Lagos"},
+  {"Nairobi", nil, "KE", -1.2921, 36.8219, 1795, "Africa/Nairobi"},
+  {"Johannesburg", nil, "ZA", -26.2041, 28.0473, 1753, "Africa/Johannesburg"},
+
+  // Americas
+  {"New York", []string{"NYC"}, "US", 40.7128, -74.0060, 10, "America/New_York"},
+  {"Chicago", nil, "US", 41.8781, -87.6298, 181, "America/Chicago"},
+  {"San Francisco", []string{"SF"}, "US", 37.7749, -122.4194, 16, "America/Los_Angeles"},
+  {"Los Angeles", []string{"LA"}, "US", 34.0522, -118.2437, 71, "America/Los_Angeles"},
+  {"Seattle", nil, "US", 47.6062, -122.3321, 53, "America/Los_Angeles"},
+  {"Anchorage", nil, "US", 61.2181, -149.9003, 31, "America/Anchorage"},
+  {"Toronto", nil, "CA", 43.6532, -79.3832, 76, "America/Toronto"},
+  {"Vancouver", nil, "CA", 49.2827, -123.1207, 70, "America/Vancouver"},
+  {"St. John's", nil, "CA", 47.5615, -52.7126, 59, "America/St_Johns"},
+  {"Mexico City", nil, "MX", 19.4326, -99.1332, 2240, "America/Mexico_City"},
+  {"Sao Paulo", []string{"São Paulo"}, "BR", -23.5505, -46.6333, 760, "America/Sao_Paulo"},
+}
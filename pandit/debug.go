@@ -0,0 +1,29 @@
+package pandit
+
+import (
+  "io/ioutil"
+  "log"
+  "os"
+)
+
+func debug(strings ...interface{}) {
+  Debug := log.New(os.Stdout,
+    "DEBUG:",
+    log.Ldate|log.Ltime|log.Lshortfile)
+
+  _, debug := os.LookupEnv("DEBUG")
+
+  if debug != true {
+    Debug.SetOutput(ioutil.Discard)
+  }
+
+  Debug.Println(strings...)
+}
+
+/**
+ * Exported for the binaries so that all the debug
+ * output is controlled by the same DEBUG variable
+ */
+func Debug(strings ...interface{}) {
+  debug(strings...)
+}
@@ -0,0 +1,59 @@
+package pandit
+
+import (
+  "errors"
+  "fmt"
+)
+
+// Sentinel errors, compare against Cause(err)
+var (
+  ErrInvalidLocation          = errors.New("invalid location")
+  ErrDateOutOfRange           = errors.New("date out of range")
+  ErrNoSunrise                = errors.New("no sunrise at this latitude")
+  ErrInconsistentVedicDay     = errors.New("inconsistent vedic day")
+  ErrNoShubhPeriod            = errors.New("no shubh period ahead")
+  ErrInvalidPolicy            = errors.New("invalid policy")
+  ErrUnknownCity              = errors.New("unknown city")
+  ErrInvalidPolarFallback     = errors.New("invalid polar fallback")
+  ErrUnknownEngine            = errors.New("unknown engine")
+  ErrInvalidTable             = errors.New("invalid sun table")
+  ErrNotInTable               = errors.New("not in sun table")
+  ErrInvalidSunriseDefinition = errors.New("invalid sunrise definition")
+  ErrUnknownMuhurat           = errors.New("unknown muhurat")
+  ErrNoMuhurat                = errors.New("no such muhurat on this day")
+)
+
+/**
+ * Error wraps one of the sentinel errors above
+ * with details about what exactly went wrong
+ */
+type Error struct {
+  Err    error
+  Detail string
+}
+
+func (e *Error) Error() string {
+  if e.Detail == "" {
+    return e.Err.Error()
+  }
+  return e.Err.Error() + ": " + e.Detail
+}
+
+func (e *Error) Unwrap() error {
+  return e.Err
+}
+
+func newError(err error, format string, args ...interface{}) error {
+  return &Error{Err: err, Detail: fmt.Sprintf(format, args...)}
+}
+
+/**
+ * Returns the sentinel error behind err, or err
+ * itself if it did not come from this package
+ */
+func Cause(err error) error {
+  if e, ok := err.(*Error); ok {
+    return e.Err
+  }
+  return err
+}
@@ -0,0 +1,170 @@
+package pandit
+
+import (
+  "sort"
+  "strings"
+  "time"
+  "unicode"
+)
+
+type City struct {
+  Name      string
+  Aliases   []string
+  Country   string
+  Latitude  float64
+  Longitude float64
+  Elevation float64
+  Timezone  string
+}
+
+func (c City) Location() Location {
+  return Location{Latitude: c.Latitude, Longitude: c.Longitude}
+}
+
+func (c City) TimeLocation() (*time.Location, error) {
+  return time.LoadLocation(c.Timezone)
+}
+
+/**
+ * Finds a city by name or alias, ignoring case, spaces and
+ * punctuation. A single close misspelling is accepted, otherwise
+ * the error suggests what might have been meant
+ */
+func LookupCity(name string) (City, error) {
+  key := normalizeCityName(name)
+  if key == "" {
+    return City{}, newError(ErrUnknownCity, "empty city name")
+  }
+
+  for _, city := range CITIES {
+    for _, candidate := range cityNames(city) {
+      if normalizeCityName(candidate) == key {
+        return city, nil
+      }
+    }
+  }
+
+  matches := rankCities(key)
+  if len(matches) > 0 && matches[0].distance <= 1 && (len(matches) == 1 || matches[1].distance > 1) {
+    return matches[0].city, nil
+  }
+
+  if len(matches) == 0 {
+    return City{}, newError(ErrUnknownCity, "%q is not in the gazetteer", name)
+  }
+  suggestions := []string{}
+  for i := 0; i < len(matches) && i < 3; i++ {
+    suggestions = append(suggestions, matches[i].city.Name)
+  }
+  return City{}, newError(ErrUnknownCity, "%q is not in the gazetteer, did you mean %s?", name, strings.Join(suggestions, ", "))
+}
+
+/**
+ * Returns up to limit cities matching query, best matches first.
+ * An empty query lists every city
+ */
+func SearchCities(query string, limit int) []City {
+  key := normalizeCityName(query)
+  cities := []City{}
+
+  if key == "" {
+    cities = append(cities, CITIES...)
+    sort.Slice(cities, func(i, j int) bool { return cities[i].Name < cities[j].Name })
+  } else {
+    for _, match := range rankCities(key) {
+      cities = append(cities, match.city)
+    }
+  }
+
+  if limit > 0 && len(cities) > limit {
+    cities = cities[:limit]
+  }
+  return cities
+}
+
+type cityMatch struct {
+  city     City
+  distance int
+}
+
+/**
+ * Scores every city against key, keeping the plausible ones.
+ * Prefix matches score 0, substring matches 1, and anything
+ * else its edit distance if that is small enough
+ */
+func rankCities(key string) []cityMatch {
+  maxDistance := 1 + len(key)/4
+  matches := []cityMatch{}
+
+  for _, city := range CITIES {
+    best := -1
+    for _, candidate := range cityNames(city) {
+      name := normalizeCityName(candidate)
+      distance := levenshtein(key, name)
+      if strings.HasPrefix(name, key) {
+        distance = 0
+      } else if len(key) >= 3 && strings.Contains(name, key) {
+        distance = 1
+      }
+      if best == -1 || distance < best {
+        best = distance
+      }
+    }
+    if best <= maxDistance {
+      matches = append(matches, cityMatch{city, best})
+    }
+  }
+
+  sort.SliceStable(matches, func(i, j int) bool {
+    if matches[i].distance != matches[j].distance {
+      return matches[i].distance < matches[j].distance
+    }
+    return matches[i].city.Name < matches[j].city.Name
+  })
+  return matches
+}
+
+func cityNames(city City) []string {
+  return append([]string{city.Name}, city.Aliases...)
+}
+
+func normalizeCityName(name string) string {
+  var b []rune
+  for _, r := range strings.ToLower(name) {
+    if unicode.IsLetter(r) || unicode.IsDigit(r) {
+      b = append(b, r)
+    }
+  }
+  return string(b)
+}
+
+func levenshtein(a string, b string) int {
+  s, t := []rune(a), []rune(b)
+  previous := make([]int, len(t)+1)
+  current := make([]int, len(t)+1)
+  for j := range previous {
+    previous[j] = j
+  }
+  for i := 1; i <= len(s); i++ {
+    current[0] = i
+    for j := 1; j <= len(t); j++ {
+      cost := 1
+      if s[i-1] == t[j-1] {
+        cost = 0
+      }
+      current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+    }
+    previous, current = current, previous
+  }
+  return previous[len(t)]
+}
+
+func min3(a, b, c int) int {
+  if b < a {
+    a = b
+  }
+  if c < a {
+    a = c
+  }
+  return a
+}
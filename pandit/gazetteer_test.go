@@ -0,0 +1,118 @@
+package pandit
+
+import (
+  "strings"
+  "testing"
+)
+
+/**
+ * A lookup is accepted when the name or an alias matches exactly
+ * after dropping case, spaces and punctuation, or when a single
+ * city scores 1 or less: a prefix scores 0, a substring of 3
+ * letters or more 1, and anything else its edit distance.
+ * Otherwise it fails, suggesting up to 3 of the best
+ */
+var LOOKUP_CASES = []struct {
+  query string
+  city  string
+  // Cities the error suggests, in order, when city is empty
+  suggest string
+}{
+  {"Mumbai", "Mumbai", ""},
+  {"  MUMBAI ", "Mumbai", ""},
+  {"Bombay", "Mumbai", ""},
+  {"New Delhi", "Delhi", ""},
+  {"newdelhi", "Delhi", ""},
+  // Accented spellings are aliases
+  {"São Paulo", "Sao Paulo", ""},
+  {"Tromsø", "Tromso", ""},
+  {"Calcutta", "Kolkata", ""},
+  // One edit away, and no other city as close
+  {"Mumbay", "Mumbai", ""},
+  {"Kolkatta", "Kolkata", ""},
+  {"Londn", "London", ""},
+  {"Parise", "Paris", ""},
+  // A prefix only one city has
+  {"beng", "Bengaluru", ""},
+  // A prefix of Delhi and a substring of Adelaide tie
+  {"del", "", "Delhi, Adelaide"},
+  {"Lon", "", "London, Longyearbyen, Shillong"},
+  {"xyzzy", "", ""},
+  {"", "", ""},
+}
+
+func TestLookupCity(t *testing.T) {
+  for _, test := range LOOKUP_CASES {
+    city, err := LookupCity(test.query)
+    if test.city != "" {
+      if err != nil || city.Name != test.city {
+        t.Errorf("%q: got %q, %v, expected %s", test.query, city.Name, err, test.city)
+      }
+      continue
+    }
+
+    if Cause(err) != ErrUnknownCity {
+      t.Errorf("%q: got %q, %v, expected an unknown city", test.query, city.Name, err)
+      continue
+    }
+    suggested := strings.Contains(err.Error(), "did you mean")
+    if test.suggest == "" && suggested {
+      t.Errorf("%q: %v, expected no suggestions", test.query, err)
+    }
+    if test.suggest != "" && !strings.HasSuffix(err.Error(), "did you mean "+test.suggest+"?") {
+      t.Errorf("%q: %v, expected it to suggest %s", test.query, err, test.suggest)
+    }
+  }
+}
+
+func TestSearchCities(t *testing.T) {
+  tests := []struct {
+    query  string
+    limit  int
+    cities string
+  }{
+    {"beng", 5, "Bengaluru"},
+    {"del", 5, "Delhi, Adelaide"},
+    {"Mumbay", 5, "Mumbai"},
+    {"Lon", 2, "London, Longyearbyen"},
+    // Everything, by name, when there is no query
+    {"", 3, "Abu Dhabi, Adelaide, Agartala"},
+    {"xyzzy", 5, ""},
+  }
+  for _, test := range tests {
+    names := []string{}
+    for _, city := range SearchCities(test.query, test.limit) {
+      names = append(names, city.Name)
+    }
+    if got := strings.Join(names, ", "); got != test.cities {
+      t.Errorf("%q: got %s, expected %s", test.query, got, test.cities)
+    }
+  }
+}
+
+func TestRankCities(t *testing.T) {
+  tests := []struct {
+    key      string
+    city     string
+    distance int
+  }{
+    {"mum", "Mumbai", 0},
+    {"umba", "Mumbai", 1},
+    {"mumbay", "Mumbai", 1},
+    {"mumbaee", "Mumbai", 2},
+  }
+  for _, test := range tests {
+    found := false
+    for _, match := range rankCities(test.key) {
+      if match.city.Name == test.city {
+        found = true
+        if match.distance != test.distance {
+          t.Errorf("%q: %s scores %d, expected %d", test.key, test.city, match.distance, test.distance)
+        }
+      }
+    }
+    if !found {
+      t.Errorf("%q: %s is not ranked", test.key, test.city)
+    }
+  }
+}
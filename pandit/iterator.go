@@ -0,0 +1,141 @@
+package pandit
+
+import (
+  "time"
+)
+
+/**
+ * Iterator walks chowgadhiyas one at a time, in either direction,
+ * across sunset, sunrise and week boundaries. Each period has the
+ * real length of its own phase and the vaar of its own vedic day.
+ *
+ * Sunrise and sunset of each calendar date are computed once and
+ * carried over, so walking a long range costs one calculation per day
+ */
+type Iterator struct {
+  calculator *Calculator
+  // The date whose sunrise started the vedic day we are
+  // in, and the date after it that holds its next sunrise
+  today      solarDate
+  tomorrow   solarDate
+  periods    []Period
+  index      int
+}
+
+/**
+ * Returns an iterator positioned at the period t falls in
+ */
+func (c *Calculator) Iterator(t time.Time) (*Iterator, error) {
+  today, tomorrow, err := c.vedicDates(t)
+  if err != nil {
+    return nil, err
+  }
+
+  it := &Iterator{calculator: c}
+  it.load(today, tomorrow)
+
+  for it.index = 0; it.index < len(it.periods)-1; it.index++ {
+    if t.Before(it.periods[it.index].End) {
+      break
+    }
+  }
+  return it, nil
+}
+
+func (it *Iterator) load(today solarDate, tomorrow solarDate) {
+  it.today = today
+  it.tomorrow = tomorrow
+  it.periods = newVedicDay(today, tomorrow).Periods()
+}
+
+// The vedic day of the period the iterator is at
+func (it *Iterator) VedicDay() VedicDay {
+  return newVedicDay(it.today, it.tomorrow)
+}
+
+// The period the iterator is currently at
+func (it *Iterator) Period() Period {
+  return it.periods[it.index]
+}
+
+/**
+ * The part of the current period that is shubh: all of it, or
+ * only the abhijit muhurat in it when the policy counts abhijit
+ * and not the chowgadhiya. ok is false when none of it is
+ */
+func (it *Iterator) ShubhPart() (Period, bool) {
+  return it.calculator.shubhPart(it.VedicDay(), it.Period())
+}
+
+/**
+ * Moves to the following period and returns it
+ */
+func (it *Iterator) Next() (Period, error) {
+  if it.index < len(it.periods)-1 {
+    it.index++
+    return it.Period(), nil
+  }
+
+  today := it.tomorrow
+  tomorrow, err := it.calculator.solarDateAfter(today, 1)
+  if err != nil {
+    return Period{}, err
+  }
+  if err := checkVedicDay(today, tomorrow); err != nil {
+    return Period{}, err
+  }
+
+  it.load(today, tomorrow)
+  it.index = 0
+  return it.Period(), nil
+}
+
+/**
+ * Moves to the preceding period and returns it
+ */
+func (it *Iterator) Prev() (Period, error) {
+  if it.index > 0 {
+    it.index--
+    return it.Period(), nil
+  }
+
+  tomorrow := it.today
+  today, err := it.calculator.solarDateAfter(tomorrow, -1)
+  if err != nil {
+    return Period{}, err
+  }
+  if err := checkVedicDay(today, tomorrow); err != nil {
+    return Period{}, err
+  }
+
+  it.load(today, tomorrow)
+  it.index = len(it.periods) - 1
+  return it.Period(), nil
+}
+
+/**
+ * Returns the first shubh period that starts after t. When the
+ * policy counts abhijit muhurat, the part of a period it covers
+ * counts too, and is returned with the start and end of that part
+ */
+func (c *Calculator) NextShubh(t time.Time) (Period, error) {
+  it, err := c.Iterator(t)
+  if err != nil {
+    return Period{}, err
+  }
+
+  limit := t.AddDate(0, 0, MAX_LOOKAHEAD_DAYS)
+  // Abhijit muhurat can still be to come in the period t falls in
+  for period := it.Period(); ; {
+    if part, ok := it.ShubhPart(); ok && part.Start.After(t) {
+      return part, nil
+    }
+    if period.Start.After(limit) {
+      return Period{}, newError(ErrNoShubhPeriod, "nothing within %d days of %v", MAX_LOOKAHEAD_DAYS, t)
+    }
+    period, err = it.Next()
+    if err != nil {
+      return Period{}, err
+    }
+  }
+}
@@ -0,0 +1,173 @@
+package pandit
+
+import (
+  "fmt"
+  "strings"
+  "time"
+)
+
+/**
+ * KaalType is an inauspicious part of the day. Each one takes one of
+ * the eighths the daytime is split into for the chowgadhiyas,
+ * which one depending on the vaar
+ */
+type KaalType int
+
+const (
+  // On a Period, that no kaal falls on it
+  NoKaal KaalType = iota
+  RahuKaal
+  Yamaganda
+  Gulika
+)
+
+// Every kaal, in the order they are listed in
+var ALL_KAALS = []KaalType{RahuKaal, Yamaganda, Gulika}
+
+var kaalNames = map[KaalType]string{
+  NoKaal:    "none",
+  RahuKaal:  "rahukaal",
+  Yamaganda: "yamaganda",
+  Gulika:    "gulika",
+}
+
+// Which eighth of the daytime, counting from 0, each kaal takes by vaar.
+// No two kaals take the same eighth on the same vaar
+var KAAL_SEGMENTS = map[KaalType]map[Vaar]int{
+  RahuKaal: map[Vaar]int{
+    Ravivaar:   7,
+    Somvaar:    1,
+    Mangalvaar: 6,
+    Budhvaar:   4,
+    Guruvaar:   5,
+    Shukravaar: 3,
+    Shanivaar:  2,
+  },
+  Yamaganda: map[Vaar]int{
+    Ravivaar:   4,
+    Somvaar:    3,
+    Mangalvaar: 2,
+    Budhvaar:   1,
+    Guruvaar:   0,
+    Shukravaar: 6,
+    Shanivaar:  5,
+  },
+  Gulika: map[Vaar]int{
+    Ravivaar:   6,
+    Somvaar:    5,
+    Mangalvaar: 4,
+    Budhvaar:   3,
+    Guruvaar:   2,
+    Shukravaar: 1,
+    Shanivaar:  0,
+  },
+}
+
+func (k KaalType) String() string {
+  if name, ok := kaalNames[k]; ok {
+    return name
+  }
+  return "unknown"
+}
+
+func ParseKaal(name string) (KaalType, error) {
+  name = strings.ToLower(strings.TrimSpace(name))
+  for _, k := range ALL_KAALS {
+    if kaalNames[k] == name {
+      return k, nil
+    }
+  }
+  return NoKaal, fmt.Errorf("unknown kaal %q", name)
+}
+
+/**
+ * The kaal that takes the index'th eighth of the daytime
+ * on vaar, NoKaal when none does
+ */
+func kaalAt(vaar Vaar, index int) KaalType {
+  for _, k := range ALL_KAALS {
+    if KAAL_SEGMENTS[k][vaar] == index {
+      return k
+    }
+  }
+  return NoKaal
+}
+
+// A kaal on a given vedic day
+type KaalPeriod struct {
+  Kaal     KaalType
+  Vaar     Vaar
+  Start    time.Time
+  End      time.Time
+  Fallback PolarFallback
+}
+
+func (p KaalPeriod) Contains(t time.Time) bool {
+  return !t.Before(p.Start) && t.Before(p.End)
+}
+
+func (p KaalPeriod) Duration() time.Duration {
+  return p.End.Sub(p.Start)
+}
+
+/**
+ * The kaal on this vedic day, with the same boundaries
+ * as the day chowgadhiya it coincides with.
+ * k is one of ALL_KAALS
+ */
+func (d VedicDay) Kaal(k KaalType) KaalPeriod {
+  segment := KAAL_SEGMENTS[k][d.Vaar]
+  return KaalPeriod{
+    Kaal:     k,
+    Vaar:     d.Vaar,
+    Start:    boundary(d.Sunrise, d.Sunset, segment, 8),
+    End:      boundary(d.Sunrise, d.Sunset, segment+1, 8),
+    Fallback: d.Fallback,
+  }
+}
+
+/**
+ * Every kaal on this vedic day, in the order they come
+ */
+func (d VedicDay) Kaals() []KaalPeriod {
+  periods := []KaalPeriod{}
+  for index := 0; index < len(d.List(Day)); index++ {
+    if k := kaalAt(d.Vaar, index); k != NoKaal {
+      periods = append(periods, d.Kaal(k))
+    }
+  }
+  return periods
+}
+
+/**
+ * Returns the kaal that t falls in, or else the next one
+ */
+func (c *Calculator) NextKaal(k KaalType, t time.Time) (KaalPeriod, error) {
+  day, err := c.VedicDay(t)
+  if err != nil {
+    return KaalPeriod{}, err
+  }
+  period := day.Kaal(k)
+  if t.Before(period.End) {
+    return period, nil
+  }
+
+  // Already over today, so it is tomorrow's
+  day, err = c.VedicDay(day.NextSunrise)
+  if err != nil {
+    return KaalPeriod{}, err
+  }
+  return day.Kaal(k), nil
+}
+
+/**
+ * Returns whether t falls in the kaal, and the kaal
+ * that it falls in or that comes next
+ */
+func (c *Calculator) InKaal(k KaalType, t time.Time) (bool, KaalPeriod, error) {
+  period, err := c.NextKaal(k, t)
+  if err != nil {
+    return false, period, err
+  }
+  return period.Contains(t), period, nil
+}
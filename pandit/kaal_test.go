@@ -0,0 +1,82 @@
+package pandit
+
+import (
+  "testing"
+  "time"
+)
+
+// The kaals for a 06:00 sunrise and 18:00 sunset, as panchangs print them
+var KAAL_REFERENCE = map[KaalType]map[Vaar]string{
+  RahuKaal: {
+    Ravivaar:   "16:30-18:00",
+    Somvaar:    "07:30-09:00",
+    Mangalvaar: "15:00-16:30",
+    Budhvaar:   "12:00-13:30",
+    Guruvaar:   "13:30-15:00",
+    Shukravaar: "10:30-12:00",
+    Shanivaar:  "09:00-10:30",
+  },
+  Yamaganda: {
+    Ravivaar:   "12:00-13:30",
+    Somvaar:    "10:30-12:00",
+    Mangalvaar: "09:00-10:30",
+    Budhvaar:   "07:30-09:00",
+    Guruvaar:   "06:00-07:30",
+    Shukravaar: "15:00-16:30",
+    Shanivaar:  "13:30-15:00",
+  },
+  Gulika: {
+    Ravivaar:   "15:00-16:30",
+    Somvaar:    "13:30-15:00",
+    Mangalvaar: "12:00-13:30",
+    Budhvaar:   "10:30-12:00",
+    Guruvaar:   "09:00-10:30",
+    Shukravaar: "07:30-09:00",
+    Shanivaar:  "06:00-07:30",
+  },
+}
+
+/**
+ * Every kaal on every vaar against the reference, and on exactly
+ * one day chowgadhiya with the same boundaries. Each wrong entry
+ * is reported, not only the first
+ */
+func TestKaalTables(t *testing.T) {
+  // 2026-10-18 is a Sunday, so this walks ravivaar to shanivaar
+  for offset := 0; offset < 7; offset++ {
+    date := time.Date(2026, 10, 18+offset, 0, 0, 0, 0, time.UTC)
+    day := VedicDay{
+      Vaar:        Vaar(date.Weekday()),
+      Sunrise:     date.Add(6 * time.Hour),
+      Sunset:      date.Add(18 * time.Hour),
+      NextSunrise: date.Add(30 * time.Hour),
+    }
+    periods := day.Periods()
+
+    for _, k := range ALL_KAALS {
+      kaal := day.Kaal(k)
+      clock := kaal.Start.Format("15:04") + "-" + kaal.End.Format("15:04")
+      if want := KAAL_REFERENCE[k][day.Vaar]; clock != want {
+        t.Errorf("%s on %s is %s, expected %s", k, day.Vaar, clock, want)
+      }
+
+      found := 0
+      for _, period := range periods {
+        if period.Kaal != k {
+          continue
+        }
+        found++
+        if period.Phase != Day || !period.Start.Equal(kaal.Start) || !period.End.Equal(kaal.End) {
+          t.Errorf("%s on %s falls on %s %s %s..%s", k, day.Vaar, period.Phase, period.Chowgadhiya, period.Start.Format("15:04"), period.End.Format("15:04"))
+        }
+      }
+      if found != 1 {
+        t.Errorf("%s on %s falls on %d chowgadhiyas", k, day.Vaar, found)
+      }
+    }
+
+    if kaals := day.Kaals(); len(kaals) != len(ALL_KAALS) {
+      t.Errorf("%d kaals on %s, expected %d", len(kaals), day.Vaar, len(ALL_KAALS))
+    }
+  }
+}
@@ -0,0 +1,184 @@
+package pandit
+
+import (
+  "errors"
+  "fmt"
+  "math"
+  "time"
+)
+
+// Samples per day in github.com/kelvins/sunrisesunset's search
+const KELVINS_SAMPLES = 24 * 60 * 60
+
+// The library's fixed zenith, a hair short of the 90°50' NOAA means
+const KELVINS_ZENITH = 90.833
+
+/**
+ * The original backend, matching github.com/kelvins/sunrisesunset
+ * to the second. It only covers 1900-2200 and UTC offsets -12..14,
+ * and a sunrise before the zone's midnight comes back as midnight.
+ * It does not give solar noon, the midpoint of sunrise and sunset
+ * is used instead, and only knows the standard sunrise, other
+ * definitions are adjusted from it
+ */
+type KelvinsSunCalculator struct{}
+
+func (KelvinsSunCalculator) Name() string {
+  return "kelvins"
+}
+
+func (KelvinsSunCalculator) SunTimes(t time.Time, latitude float64, longitude float64, definition SunriseDefinition) (SunTimes, error) {
+  loc := t.Location()
+
+  // The offset in force on this date, at noon so that a DST
+  // switch in the small hours has already happened. This is
+  // what the sunrise and sunset clock times will be in
+  _, offset := time.Date(t.Year(), t.Month(), t.Day(), 12, 0, 0, 0, loc).Zone()
+  date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+  // The library only finds events within the 24 hours after midnight
+  // at the given offset. Zones like Pacific/Apia (+13 at 171°W) are a
+  // whole day away from their longitude, so the same instants are
+  // worked out a day earlier, 24 hours behind (or the other way round)
+  solarOffset := float64(offset)/60/60 - longitude/15
+  if solarOffset > 12 {
+    offset -= 24 * 60 * 60
+    date = date.AddDate(0, 0, -1)
+  } else if solarOffset < -12 {
+    offset += 24 * 60 * 60
+    date = date.AddDate(0, 0, 1)
+  }
+
+  sunrise, sunset, err := KelvinsSunriseSunset(latitude, longitude, float64(offset)/60/60, date)
+
+  if err != nil {
+    return SunTimes{}, translateSunriseSunsetError(err, t)
+  }
+
+  // The library does not complain when the sun never crosses
+  // the horizon, it just hands back midnight for both
+  if !sunset.After(sunrise) {
+    return SunTimes{}, newError(ErrNoSunrise, "latitude %v on %s", latitude, t.Format("2006-01-02"))
+  }
+
+  // Only the clock part of what the library returns is meaningful,
+  // add it to midnight of this date in the offset it was computed for
+  base := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.FixedZone("", offset))
+  sunrise = base.Add(sunrise.Sub(time.Time{}))
+  sunset = base.Add(sunset.Sub(time.Time{}))
+  times := SunTimes{
+    Sunrise:   sunrise,
+    Sunset:    sunset,
+    SolarNoon: sunrise.Add(sunset.Sub(sunrise) / 2),
+  }
+  return adjustToDefinition(times, t, latitude, longitude, definition)
+}
+
+/**
+ * Gives the same results and errors as sunrisesunset.GetSunriseSunset
+ * without evaluating every second of the day. The library looks at
+ * sample i as if it were i/86399 of the way through the day, rounds
+ * the hour angle to whole seconds and returns the sample closest to
+ * the event, so the same is done at the few samples around the root
+ */
+func KelvinsSunriseSunset(latitude float64, longitude float64, utcOffset float64, date time.Time) (time.Time, time.Time, error) {
+  switch {
+  case latitude < -90 || latitude > 90:
+    return time.Time{}, time.Time{}, errors.New("Invalid latitude")
+  case longitude < -180 || longitude > 180:
+    return time.Time{}, time.Time{}, errors.New("Invalid longitude")
+  case utcOffset < -12 || utcOffset > 14:
+    return time.Time{}, time.Time{}, errors.New("Invalid UTC offset")
+  case date.Before(time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)) || date.After(time.Date(2200, 1, 1, 0, 0, 0, 0, time.UTC)):
+    return time.Time{}, time.Time{}, errors.New("Invalid date")
+  }
+
+  days := int64(date.Sub(time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)) / (24 * time.Hour))
+  sunrise := kelvinsSample(days, latitude, longitude, utcOffset, -1)
+  sunset := kelvinsSample(days, latitude, longitude, utcOffset, 1)
+  return time.Time{}.Add(time.Duration(sunrise) * time.Second), time.Time{}.Add(time.Duration(sunset) * time.Second), nil
+}
+
+/**
+ * The library's sample closest to sunrise (direction -1)
+ * or sunset (direction 1). Ties go to the earlier sample
+ */
+func kelvinsSample(days int64, latitude float64, longitude float64, utcOffset float64, direction float64) int {
+  clamp := func(sample int) int {
+    if sample < 0 {
+      return 0
+    }
+    if sample > KELVINS_SAMPLES-1 {
+      return KELVINS_SAMPLES - 1
+    }
+    return sample
+  }
+
+  // Each sample is a little over a second, so moving to
+  // where the event looks to be lands next to it in a few tries
+  sample := KELVINS_SAMPLES / 2
+  for i := 0; i < 4; i++ {
+    event, _, ok := kelvinsEvent(sample, days, latitude, longitude, utcOffset, direction)
+    // No sunrise, the library ends up at its first sample
+    if !ok {
+      return 0
+    }
+    sample = clamp(int(math.Floor(event * (KELVINS_SAMPLES - 1) / KELVINS_SAMPLES)))
+  }
+
+  best, bestResidual := -1, 0.0
+  for candidate := clamp(sample - 2); candidate <= clamp(sample+2); candidate++ {
+    _, residual, ok := kelvinsEvent(candidate, days, latitude, longitude, utcOffset, direction)
+    if ok && (best == -1 || residual < bestResidual) {
+      best, bestResidual = candidate, residual
+    }
+  }
+  if best == -1 {
+    return 0
+  }
+  return best
+}
+
+/**
+ * With the sun as it is at sample, the event's time in seconds
+ * after midnight, and how far the sample is from it once the hour
+ * angle is rounded like the library does. Not ok without an event
+ */
+func kelvinsEvent(sample int, days int64, latitude float64, longitude float64, utcOffset float64, direction float64) (float64, float64, bool) {
+  fraction := float64(sample) / (KELVINS_SAMPLES - 1)
+  declination, equationOfTime := sunPositionAt(float64(days) + 2415018.5 + fraction - utcOffset/24)
+  cosHourAngle := math.Cos(deg2rad(KELVINS_ZENITH))/(math.Cos(deg2rad(latitude))*math.Cos(deg2rad(declination))) -
+    math.Tan(deg2rad(latitude))*math.Tan(deg2rad(declination))
+  if !(cosHourAngle >= -1 && cosHourAngle <= 1) {
+    return 0, 0, false
+  }
+  hourAngle := rad2deg(math.Acos(cosHourAngle))
+  solarNoon := (720 - 4*longitude - equationOfTime + utcOffset*60) * 60
+  event := solarNoon + direction*hourAngle*4*60
+  residual := solarNoon + direction*float64(roundHalfUp(hourAngle*4*60)) - KELVINS_SAMPLES*fraction
+  return event, math.Abs(residual), true
+}
+
+func roundHalfUp(value float64) int {
+  if value < 0 {
+    return int(value - 0.5)
+  }
+  return int(value + 0.5)
+}
+
+/**
+ * sunrisesunset only gives us error strings,
+ * map them onto our sentinel errors
+ */
+func translateSunriseSunsetError(err error, t time.Time) error {
+  switch err.Error() {
+  case "Invalid latitude", "Invalid longitude":
+    return newError(ErrInvalidLocation, "%v", err)
+  case "Invalid UTC offset":
+    _, offset := t.Zone()
+    return newError(ErrInvalidLocation, "unsupported UTC offset %ds", offset)
+  case "Invalid date":
+    return newError(ErrDateOutOfRange, "%s is outside 1900-2200", t.Format("2006-01-02"))
+  }
+  return fmt.Errorf("sunrise/sunset calculations failed: %v", err)
+}
@@ -0,0 +1,101 @@
+package pandit
+
+import (
+  "testing"
+  "time"
+
+  "github.com/kelvins/sunrisesunset"
+)
+
+// City and date pairs to compare with the vendored library
+const LIBRARY_SAMPLES = 200
+
+/**
+ * Runs the analytic solver and the vendored library on cities from
+ * the gazetteer over a spread of dates, and fails if any sunrise
+ * or sunset differs by more than a second. Days on the edge of the
+ * midnight sun or polar night, where the library's search finds
+ * meaningless events, are counted but not compared. The library
+ * takes a good fraction of a second a call, so -short skips it
+ */
+func TestKelvinsMatchesLibrary(t *testing.T) {
+  if testing.Short() {
+    t.Skip("the library's search is slow")
+  }
+  worst := time.Duration(0)
+  exact, polar := 0, 0
+  for i := 0; i < LIBRARY_SAMPLES; i++ {
+    city := CITIES[(i*7)%len(CITIES)]
+    date := time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, (i*7919)%(300*365))
+    zone, err := city.TimeLocation()
+    if err != nil {
+      t.Fatal(err)
+    }
+    _, offset := time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, zone).Zone()
+    utcOffset := float64(offset) / 60 / 60
+    if utcOffset < -12 || utcOffset > 14 {
+      continue
+    }
+
+    sunrise, sunset, err := sunrisesunset.GetSunriseSunset(city.Latitude, city.Longitude, utcOffset, date)
+    analyticSunrise, analyticSunset, analyticErr := KelvinsSunriseSunset(city.Latitude, city.Longitude, utcOffset, date)
+    if (err == nil) != (analyticErr == nil) {
+      t.Errorf("%s on %s: library said %v, solver said %v", city.Name, date.Format("2006-01-02"), err, analyticErr)
+      continue
+    }
+    if err != nil {
+      continue
+    }
+    if !sunset.After(sunrise) || !analyticSunset.After(analyticSunrise) {
+      polar++
+      continue
+    }
+
+    diff := absDuration(sunrise.Sub(analyticSunrise))
+    if other := absDuration(sunset.Sub(analyticSunset)); other > diff {
+      diff = other
+    }
+    if diff == 0 {
+      exact++
+    }
+    if diff > worst {
+      worst = diff
+    }
+    if diff > time.Second {
+      t.Errorf("%s on %s: library gave %s-%s, solver gave %s-%s", city.Name, date.Format("2006-01-02"),
+        sunrise.Format("15:04:05"), sunset.Format("15:04:05"), analyticSunrise.Format("15:04:05"), analyticSunset.Format("15:04:05"))
+    }
+  }
+  t.Logf("%d of %d samples exactly, worst difference %s, %d polar days skipped", exact, LIBRARY_SAMPLES, worst, polar)
+}
+
+func absDuration(d time.Duration) time.Duration {
+  if d < 0 {
+    return -d
+  }
+  return d
+}
+
+var BENCH_LOCATION = Location{Latitude: 26.7880, Longitude: 82.1986, Timezone: time.FixedZone("IST", 19800)}
+var BENCH_DATE = time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+func BenchmarkLibrarySunriseSunset(b *testing.B) {
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    sunrisesunset.GetSunriseSunset(BENCH_LOCATION.Latitude, BENCH_LOCATION.Longitude, 5.5, BENCH_DATE)
+  }
+}
+
+func BenchmarkKelvinsSunriseSunset(b *testing.B) {
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    KelvinsSunriseSunset(BENCH_LOCATION.Latitude, BENCH_LOCATION.Longitude, 5.5, BENCH_DATE)
+  }
+}
+
+func BenchmarkNOAASunTimes(b *testing.B) {
+  b.ReportAllocs()
+  for i := 0; i < b.N; i++ {
+    NOAASunCalculator{}.SunTimes(BENCH_DATE, BENCH_LOCATION.Latitude, BENCH_LOCATION.Longitude, DefaultSunriseDefinition)
+  }
+}
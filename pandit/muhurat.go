@@ -0,0 +1,181 @@
+package pandit
+
+import (
+  "sort"
+  "strings"
+  "time"
+)
+
+/**
+ * MuhuratType is a named window of the vedic day that is not
+ * one of the chowgadhiyas, worked out from muhurtas instead:
+ * fifteenths of the daytime or of the night
+ */
+type MuhuratType int
+
+const (
+  // The 8th muhurta of the daytime, around solar noon
+  AbhijitMuhurat MuhuratType = iota
+  // The 14th muhurta of the night, ending one muhurta before sunrise
+  BrahmaMuhurta
+  // Dawn, the last muhurta and a half before sunrise
+  PratahSandhya
+  // Midday, a daytime muhurta and a half centred on solar noon
+  MadhyahnaSandhya
+  // Dusk, the first muhurta and a half after sunset
+  SayamSandhya
+)
+
+// Every muhurat, in the order they are listed in
+var ALL_MUHURATS = []MuhuratType{AbhijitMuhurat, BrahmaMuhurta, PratahSandhya, MadhyahnaSandhya, SayamSandhya}
+
+var muhuratNames = map[MuhuratType]string{
+  AbhijitMuhurat:   "abhijit",
+  BrahmaMuhurta:    "brahma",
+  PratahSandhya:    "pratah-sandhya",
+  MadhyahnaSandhya: "madhyahna-sandhya",
+  SayamSandhya:     "sayam-sandhya",
+}
+
+// Muhurtas in a daytime, and in a night
+const MUHURTAS = 15
+
+func (m MuhuratType) String() string {
+  if name, ok := muhuratNames[m]; ok {
+    return name
+  }
+  return "unknown"
+}
+
+func ParseMuhurat(name string) (MuhuratType, error) {
+  name = strings.ToLower(strings.TrimSpace(name))
+  for _, m := range ALL_MUHURATS {
+    if muhuratNames[m] == name {
+      return m, nil
+    }
+  }
+  names := []string{}
+  for _, m := range ALL_MUHURATS {
+    names = append(names, m.String())
+  }
+  return 0, newError(ErrUnknownMuhurat, "%q, expected one of %s", name, strings.Join(names, ", "))
+}
+
+// A muhurat on a given vedic day
+type MuhuratPeriod struct {
+  Muhurat  MuhuratType
+  Vaar     Vaar
+  Start    time.Time
+  End      time.Time
+  Fallback PolarFallback
+}
+
+func (p MuhuratPeriod) Contains(t time.Time) bool {
+  return !t.Before(p.Start) && t.Before(p.End)
+}
+
+func (p MuhuratPeriod) Duration() time.Duration {
+  return p.End.Sub(p.Start)
+}
+
+// One fifteenth of the daytime
+func (d VedicDay) dayMuhurta() time.Duration {
+  return d.Sunset.Sub(d.Sunrise) / MUHURTAS
+}
+
+// One fifteenth of the night, from sunset to the next sunrise
+func (d VedicDay) nightMuhurta() time.Duration {
+  return d.NextSunrise.Sub(d.Sunset) / MUHURTAS
+}
+
+// Solar noon, or the middle of the daytime when it is not known
+func (d VedicDay) noon() time.Time {
+  if d.SolarNoon.IsZero() {
+    return d.Sunrise.Add(d.Sunset.Sub(d.Sunrise) / 2)
+  }
+  return d.SolarNoon
+}
+
+func (d VedicDay) muhuratPeriod(m MuhuratType, start time.Time, end time.Time) MuhuratPeriod {
+  return MuhuratPeriod{
+    Muhurat:  m,
+    Vaar:     d.Vaar,
+    Start:    start.Round(time.Second),
+    End:      end.Round(time.Second),
+    Fallback: d.Fallback,
+  }
+}
+
+/**
+ * The muhurat on this vedic day. Brahma muhurta and pratah
+ * sandhya come from its night, so they are the ones before
+ * the next sunrise. ok is false when the vedic day has none
+ */
+func (d VedicDay) Muhurat(m MuhuratType) (period MuhuratPeriod, ok bool) {
+  night := d.nightMuhurta()
+  switch m {
+  case AbhijitMuhurat:
+    return d.Abhijit()
+  case BrahmaMuhurta:
+    return d.muhuratPeriod(m, d.NextSunrise.Add(-2*night), d.NextSunrise.Add(-night)), true
+  case PratahSandhya:
+    return d.muhuratPeriod(m, d.NextSunrise.Add(-3*night/2), d.NextSunrise), true
+  case MadhyahnaSandhya:
+    half := 3 * d.dayMuhurta() / 4
+    return d.muhuratPeriod(m, d.noon().Add(-half), d.noon().Add(half)), true
+  case SayamSandhya:
+    return d.muhuratPeriod(m, d.Sunset, d.Sunset.Add(3*night/2)), true
+  }
+  return MuhuratPeriod{}, false
+}
+
+/**
+ * Abhijit muhurat lasts one daytime muhurta and is centred on
+ * solar noon, or on the middle of the daytime when solar noon
+ * is not known. ok is false on budhvaar, which has none
+ */
+func (d VedicDay) Abhijit() (period MuhuratPeriod, ok bool) {
+  if d.Vaar == Budhvaar {
+    return MuhuratPeriod{}, false
+  }
+  half := d.dayMuhurta() / 2
+  return d.muhuratPeriod(AbhijitMuhurat, d.noon().Add(-half), d.noon().Add(half)), true
+}
+
+/**
+ * Every muhurat on this vedic day, in the order they start
+ */
+func (d VedicDay) Muhurats() []MuhuratPeriod {
+  periods := []MuhuratPeriod{}
+  for _, m := range ALL_MUHURATS {
+    if period, ok := d.Muhurat(m); ok {
+      periods = append(periods, period)
+    }
+  }
+  sort.SliceStable(periods, func(i, j int) bool {
+    return periods[i].Start.Before(periods[j].Start)
+  })
+  return periods
+}
+
+/**
+ * Returns the muhurat that t falls in, or else the next
+ * one, skipping over days without one
+ */
+func (c *Calculator) NextMuhurat(m MuhuratType, t time.Time) (MuhuratPeriod, error) {
+  day, err := c.VedicDay(t)
+  if err != nil {
+    return MuhuratPeriod{}, err
+  }
+  // Today's may be over, and tomorrow may be budhvaar
+  for i := 0; i < 3; i++ {
+    if period, ok := day.Muhurat(m); ok && t.Before(period.End) {
+      return period, nil
+    }
+    day, err = c.VedicDay(day.NextSunrise)
+    if err != nil {
+      return MuhuratPeriod{}, err
+    }
+  }
+  return MuhuratPeriod{}, newError(ErrNoMuhurat, "no %s in the three vedic days from %v", m, t)
+}
@@ -0,0 +1,162 @@
+package pandit
+
+import (
+  "math"
+  "time"
+)
+
+/**
+ * Solves the NOAA (Meeus) equations for the events directly
+ * instead of searching the day. Same model as the kelvins
+ * backend, so results agree to a few seconds, but thousands of
+ * times faster and without its date range
+ */
+type NOAASunCalculator struct{}
+
+func (NOAASunCalculator) Name() string {
+  return "noaa"
+}
+
+func (NOAASunCalculator) SunTimes(t time.Time, latitude float64, longitude float64, definition SunriseDefinition) (SunTimes, error) {
+  if !(latitude >= -90 && latitude <= 90) || !(longitude >= -180 && longitude <= 180) {
+    return SunTimes{}, newError(ErrInvalidLocation, "latitude %v, longitude %v", latitude, longitude)
+  }
+
+  // Local noon picks the transit that belongs to this date,
+  // whatever the zone's offset from its longitude
+  noon := solarTransit(time.Date(t.Year(), t.Month(), t.Day(), 12, 0, 0, 0, t.Location()), longitude)
+
+  zenith := definition.Zenith()
+  times, ok := sunTimesAround(noon, zenith, latitude)
+  if !ok {
+    return SunTimes{}, newError(ErrNoSunrise, "latitude %v on %s", latitude, t.Format("2006-01-02"))
+  }
+
+  // A zone well behind its longitude (say UTC in India) sees the
+  // sunrise of that transit before its own midnight, and one well
+  // ahead of it after the next. Like the kelvins backend, use the
+  // transit a day over so the sunrise falls on this date. A date
+  // shortened by DST can have none, it gets the first one after it
+  if times.Sunrise.Before(midnight(t)) {
+    times, ok = sunTimesAround(solarTransit(noon.Add(24*time.Hour), longitude), zenith, latitude)
+  } else if !times.Sunrise.Before(addDays(t, 1)) {
+    earlier, earlierOk := sunTimesAround(solarTransit(noon.Add(-24*time.Hour), longitude), zenith, latitude)
+    if earlierOk && !earlier.Sunrise.Before(midnight(t)) {
+      times = earlier
+    }
+  }
+  if !ok {
+    return SunTimes{}, newError(ErrNoSunrise, "latitude %v on %s", latitude, t.Format("2006-01-02"))
+  }
+  return times, nil
+}
+
+/**
+ * The sunrise and sunset either side of the transit at noon.
+ * Not ok when the sun does not cross the horizon that day
+ */
+func sunTimesAround(noon time.Time, zenith float64, latitude float64) (SunTimes, bool) {
+  sunrise, ok := horizonCrossing(noon, zenith, latitude, -1)
+  if !ok {
+    return SunTimes{}, false
+  }
+  sunset, ok := horizonCrossing(noon, zenith, latitude, 1)
+  if !ok {
+    return SunTimes{}, false
+  }
+
+  return SunTimes{
+    Sunrise:   sunrise.Round(time.Second),
+    Sunset:    sunset.Round(time.Second),
+    SolarNoon: noon.Round(time.Second),
+  }, true
+}
+
+/**
+ * The sun's meridian transit closest to near
+ */
+func solarTransit(near time.Time, longitude float64) time.Time {
+  transit := near
+  // Equation of time barely moves in a day, twice is plenty
+  for i := 0; i < 2; i++ {
+    _, equationOfTime := sunPosition(transit)
+    utc := transit.UTC()
+    day := time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC)
+    transit = day.Add(minutes(720 - 4*longitude - equationOfTime))
+    if transit.Sub(near) > 12*time.Hour {
+      transit = transit.Add(-24 * time.Hour)
+    } else if transit.Sub(near) < -12*time.Hour {
+      transit = transit.Add(24 * time.Hour)
+    }
+  }
+  return transit
+}
+
+/**
+ * When the sun's centre reaches zenith before (direction -1) or
+ * after (direction 1) the transit at noon, refining with the sun's
+ * position at the event itself. Not ok when it never does that day
+ */
+func horizonCrossing(noon time.Time, zenith float64, latitude float64, direction float64) (time.Time, bool) {
+  _, noonEquationOfTime := sunPosition(noon)
+  event := noon
+  for i := 0; i < 3; i++ {
+    declination, equationOfTime := sunPosition(event)
+    cosHourAngle := (math.Cos(deg2rad(zenith)) - math.Sin(deg2rad(latitude))*math.Sin(deg2rad(declination))) /
+      (math.Cos(deg2rad(latitude)) * math.Cos(deg2rad(declination)))
+    if !(cosHourAngle >= -1 && cosHourAngle <= 1) {
+      return time.Time{}, false
+    }
+    hourAngle := rad2deg(math.Acos(cosHourAngle))
+    // The transit as it would be with the equation of time at the event
+    event = noon.Add(minutes(noonEquationOfTime - equationOfTime + direction*4*hourAngle))
+  }
+  return event, true
+}
+
+/**
+ * The sun's declination in degrees and the equation of time
+ * in minutes at instant t, from the NOAA solar calculator
+ */
+func sunPosition(t time.Time) (float64, float64) {
+  return sunPositionAt(float64(t.UnixNano())/86400e9 + 2440587.5)
+}
+
+func sunPositionAt(julianDay float64) (float64, float64) {
+  julianCentury := (julianDay - 2451545.0) / 36525.0
+
+  geomMeanLongSun := math.Mod(280.46646+julianCentury*(36000.76983+julianCentury*0.0003032), 360.0)
+  geomMeanAnomSun := 357.52911 + julianCentury*(35999.05029-0.0001537*julianCentury)
+  eccentEarthOrbit := 0.016708634 - julianCentury*(0.000042037+0.0000001267*julianCentury)
+  sunEqCtr := math.Sin(deg2rad(geomMeanAnomSun))*(1.914602-julianCentury*(0.004817+0.000014*julianCentury)) +
+    math.Sin(deg2rad(2*geomMeanAnomSun))*(0.019993-0.000101*julianCentury) +
+    math.Sin(deg2rad(3*geomMeanAnomSun))*0.000289
+  sunAppLong := geomMeanLongSun + sunEqCtr - 0.00569 - 0.00478*math.Sin(deg2rad(125.04-1934.136*julianCentury))
+  meanObliqEcliptic := 23.0 + (26.0+(21.448-julianCentury*(46.815+julianCentury*(0.00059-julianCentury*0.001813)))/60.0)/60.0
+  obliqCorr := meanObliqEcliptic + 0.00256*math.Cos(deg2rad(125.04-1934.136*julianCentury))
+
+  declination := rad2deg(math.Asin(math.Sin(deg2rad(obliqCorr)) * math.Sin(deg2rad(sunAppLong))))
+
+  y := math.Tan(deg2rad(obliqCorr/2)) * math.Tan(deg2rad(obliqCorr/2))
+  l0 := deg2rad(geomMeanLongSun)
+  m := deg2rad(geomMeanAnomSun)
+  equationOfTime := 4 * rad2deg(y*math.Sin(2*l0)-
+    2*eccentEarthOrbit*math.Sin(m)+
+    4*eccentEarthOrbit*y*math.Sin(m)*math.Cos(2*l0)-
+    0.5*y*y*math.Sin(4*l0)-
+    1.25*eccentEarthOrbit*eccentEarthOrbit*math.Sin(2*m))
+
+  return declination, equationOfTime
+}
+
+func minutes(m float64) time.Duration {
+  return time.Duration(m * float64(time.Minute))
+}
+
+func deg2rad(degrees float64) float64 {
+  return degrees * (math.Pi / 180.0)
+}
+
+func rad2deg(radians float64) float64 {
+  return radians * (180.0 / math.Pi)
+}
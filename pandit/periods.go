@@ -0,0 +1,28 @@
+package pandit
+
+import (
+  "time"
+)
+
+/**
+ * Walks vedic day after vedic day and calls visit with every
+ * period that overlaps from..to, in order. Walking stops early
+ * when visit returns false
+ */
+func (c *Calculator) Periods(from time.Time, to time.Time, visit func(Period) bool) error {
+  it, err := c.Iterator(from)
+  if err != nil {
+    return err
+  }
+
+  for period := it.Period(); period.Start.Before(to); {
+    if !visit(period) {
+      return nil
+    }
+    period, err = it.Next()
+    if err != nil {
+      return err
+    }
+  }
+  return nil
+}
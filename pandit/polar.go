@@ -0,0 +1,123 @@
+package pandit
+
+import (
+  "math"
+  "strings"
+  "time"
+)
+
+/**
+ * How days without a sunrise or sunset are handled, past
+ * the polar circles. As a setting FallbackNone means such days
+ * are undefined and fail with ErrNoSunrise, on a Period it
+ * means the sun rose and set as usual
+ */
+type PolarFallback int
+
+const (
+  FallbackNone PolarFallback = iota
+  // Use CLAMP_MARGIN_DEGREES inside the latitude where the sun rises and sets all year
+  FallbackClamp
+  // Use fixed 06:00 and 18:00 local civil boundaries
+  FallbackCivil
+)
+
+var polarFallbackNames = map[PolarFallback]string{
+  FallbackNone:  "none",
+  FallbackClamp: "clamp",
+  FallbackCivil: "civil",
+}
+
+// The most the sun's declination gets, the obliquity of the ecliptic
+const MAX_DECLINATION_DEGREES = 23.44
+
+// How far inside the last latitude with a sunrise all year clamping
+// goes, so that the shortest day and night still last a few hours
+const CLAMP_MARGIN_DEGREES = 2.0
+
+// How close to the equator clamping steps each time the sun still misbehaves
+const CLAMP_STEP_DEGREES = 0.25
+
+func (f PolarFallback) String() string {
+  if name, ok := polarFallbackNames[f]; ok {
+    return name
+  }
+  return "unknown"
+}
+
+/**
+ * Accepts none (or undefined), clamp and civil
+ */
+func ParsePolarFallback(value string) (PolarFallback, error) {
+  value = strings.ToLower(strings.TrimSpace(value))
+  if value == "" || value == "undefined" {
+    return FallbackNone, nil
+  }
+  for f, name := range polarFallbackNames {
+    if name == value {
+      return f, nil
+    }
+  }
+  return FallbackNone, newError(ErrInvalidPolarFallback, "%q, expected none, clamp or civil", value)
+}
+
+/**
+ * A vedic day spans two dates, if either needed a fallback
+ * the whole day did
+ */
+func combineFallbacks(a PolarFallback, b PolarFallback) PolarFallback {
+  if a != FallbackNone {
+    return a
+  }
+  return b
+}
+
+/**
+ * The latitude, on the equator side of the polar circle, where the
+ * sun rises and sets every day of the year with the definition's
+ * depression and still clears it by CLAMP_MARGIN_DEGREES at the
+ * solstices. The midnight sun is the tighter limit, as the sun has
+ * to get further below the horizon than above it. It depends on
+ * nothing but the definition, so it is the same every day and for
+ * every engine
+ */
+func clampLatitude(definition SunriseDefinition) float64 {
+  depression := definition.Zenith() - 90
+  return 90 - MAX_DECLINATION_DEGREES - depression - CLAMP_MARGIN_DEGREES
+}
+
+/**
+ * The sun times at clampLatitude, on our side of the equator
+ * and at our longitude
+ */
+func (c *Calculator) clampedSunTimes(t time.Time) (SunTimes, error) {
+  latitude := c.Location.Latitude
+  hemisphere := 1.0
+  if latitude < 0 {
+    hemisphere = -1.0
+  }
+  limit := math.Min(math.Abs(latitude), clampLatitude(c.Definition))
+
+  // Only a very high elevation should need more than one step
+  var err error
+  for clamped := limit; clamped > 0; clamped -= CLAMP_STEP_DEGREES {
+    var times SunTimes
+    times, err = c.sunTimesAt(t, hemisphere*clamped)
+    if err == nil {
+      debug("Clamped latitude", latitude, "to", hemisphere*clamped)
+      return times, nil
+    }
+    if Cause(err) != ErrNoSunrise {
+      return SunTimes{}, err
+    }
+  }
+  return SunTimes{}, err
+}
+
+func civilSunTimes(t time.Time) SunTimes {
+  return SunTimes{
+    Sunrise:   time.Date(t.Year(), t.Month(), t.Day(), 6, 0, 0, 0, t.Location()),
+    Sunset:    time.Date(t.Year(), t.Month(), t.Day(), 18, 0, 0, 0, t.Location()),
+    SolarNoon: time.Date(t.Year(), t.Month(), t.Day(), 12, 0, 0, 0, t.Location()),
+  }
+}
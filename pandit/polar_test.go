@@ -0,0 +1,136 @@
+package pandit
+
+import (
+  "testing"
+  "time"
+)
+
+// Tromso's polar night starts around 11-27 and its midnight sun around 05-20
+var POLAR_TRANSITIONS = []string{"2026-11-20", "2027-05-14"}
+
+// Days walked from each transition date
+const POLAR_DAYS = 14
+
+func tromsoCalculator(t *testing.T, engine SunCalculator, polar PolarFallback) (*Calculator, *time.Location) {
+  calculator, timezone := cityCalculator(t, "Tromso", engine)
+  calculator.Polar = polar
+  return calculator, timezone
+}
+
+/**
+ * Walks Tromso into polar night and into the midnight sun with each
+ * fallback, and checks every vedic day either fails the way the
+ * fallback says or comes out whole
+ */
+func TestPolarFallbacks(t *testing.T) {
+  for _, engine := range []SunCalculator{NOAASunCalculator{}, KelvinsSunCalculator{}} {
+    for _, polar := range []PolarFallback{FallbackNone, FallbackClamp, FallbackCivil} {
+      calculator, timezone := tromsoCalculator(t, engine, polar)
+      normal, fallback := 0, 0
+      for _, transition := range POLAR_TRANSITIONS {
+        start, err := time.ParseInLocation("2006-01-02", transition, timezone)
+        if err != nil {
+          t.Fatal(err)
+        }
+        var previous VedicDay
+        previousOwn := false
+        for i := 0; i < POLAR_DAYS; i++ {
+          date := start.AddDate(0, 0, i)
+          name := engine.Name() + " " + polar.String() + " " + date.Format("2006-01-02")
+          day, err := calculator.VedicDayOn(date)
+          if polar == FallbackNone {
+            if err != nil && Cause(err) != ErrNoSunrise {
+              t.Errorf("%s: %v", name, err)
+            }
+            if err == nil {
+              normal++
+            } else {
+              fallback++
+            }
+            continue
+          }
+          if err != nil {
+            t.Errorf("%s: %v", name, err)
+            continue
+          }
+          // Whether the date's own sunrise needed the fallback, rather
+          // than only the next one, which the vedic day is flagged for too
+          _, err = calculator.SunTimes(date)
+          own := Cause(err) == ErrNoSunrise
+          if day.Fallback == FallbackNone {
+            normal++
+          } else if day.Fallback != polar {
+            t.Errorf("%s: fallback is %s", name, day.Fallback)
+          } else {
+            fallback++
+            if own {
+              checkPolarDay(t, name, day, previous, previousOwn, polar)
+            }
+          }
+          if len(day.Periods()) != 16 {
+            t.Errorf("%s: %d periods", name, len(day.Periods()))
+          }
+          previous, previousOwn = day, own
+        }
+      }
+      // Each walk starts with the sun rising and setting as usual
+      if normal == 0 || fallback == 0 {
+        t.Errorf("%s %s: %d normal and %d fallback days, expected some of each", engine.Name(), polar, normal, fallback)
+      }
+    }
+  }
+}
+
+/**
+ * A vedic day whose own date needed the fallback, previousOwn
+ * says whether the day before it did too
+ */
+func checkPolarDay(t *testing.T, name string, day VedicDay, previous VedicDay, previousOwn bool, polar PolarFallback) {
+  switch polar {
+  case FallbackCivil:
+    if day.Sunrise.Format("15:04:05") != "06:00:00" || day.Sunset.Format("15:04:05") != "18:00:00" {
+      t.Errorf("%s: civil daytime from %s to %s", name, day.Sunrise.Format("15:04:05"), day.Sunset.Format("15:04:05"))
+    }
+  case FallbackClamp:
+    // Daytime and night both last a few hours, never a few minutes
+    if length := day.Sunset.Sub(day.Sunrise); length < 2*time.Hour || length > 22*time.Hour {
+      t.Errorf("%s: clamped daytime lasts %s", name, length)
+    }
+    // and a clamped day follows on from the one before it
+    if previousOwn {
+      for _, pair := range [][2]time.Time{{previous.Sunrise, day.Sunrise}, {previous.Sunset, day.Sunset}} {
+        if drift := pair[1].Sub(pair[0].AddDate(0, 0, 1)); drift < -5*time.Minute || drift > 5*time.Minute {
+          t.Errorf("%s: clamped times moved %s from the day before", name, drift)
+        }
+      }
+    }
+  }
+}
+
+/**
+ * Clamping goes to the same latitude whatever the engine, so the
+ * engines agree on a clamped day as closely as anywhere else
+ */
+func TestClampEnginesAgree(t *testing.T) {
+  noaa, timezone := tromsoCalculator(t, NOAASunCalculator{}, FallbackClamp)
+  kelvins, _ := tromsoCalculator(t, KelvinsSunCalculator{}, FallbackClamp)
+  for _, value := range []string{"2026-12-20", "2026-12-21", "2026-12-22", "2027-06-21"} {
+    date, err := time.ParseInLocation("2006-01-02", value, timezone)
+    if err != nil {
+      t.Fatal(err)
+    }
+    ours, err := noaa.VedicDayOn(date)
+    if err != nil {
+      t.Fatal(err)
+    }
+    theirs, err := kelvins.VedicDayOn(date)
+    if err != nil {
+      t.Fatal(err)
+    }
+    for _, pair := range [][2]time.Time{{ours.Sunrise, theirs.Sunrise}, {ours.Sunset, theirs.Sunset}, {ours.NextSunrise, theirs.NextSunrise}} {
+      if diff := pair[0].Sub(pair[1]); diff < -5*time.Second || diff > 5*time.Second {
+        t.Errorf("%s: noaa gives %s, kelvins %s", value, pair[0].Format("15:04:05"), pair[1].Format("15:04:05"))
+      }
+    }
+  }
+}
@@ -0,0 +1,303 @@
+package pandit
+
+import (
+  "strings"
+)
+
+/**
+ * Policy decides which chowgadhiyas count as shubh.
+ * There is some confusion as to whether Chal is considered
+ * Shubh or not, and teams follow different conventions,
+ * so the allowed chowgadhiyas can differ by day and night
+ */
+type Policy struct {
+  // Empty for custom policies
+  Name    string
+  Day     []Chowgadhiya
+  Night   []Chowgadhiya
+  // Kaals during which nothing is shubh whatever the chowgadhiya.
+  // Named and parsed policies avoid DEFAULT_AVOID unless told otherwise
+  Avoid   []KaalType
+  // Whether abhijit muhurat is shubh whatever the chowgadhiya or kaal
+  Abhijit bool
+  // Kaals avoided even during abhijit muhurat
+  Refuse  []KaalType
+}
+
+// Yamaganda and gulika are ashubh unless a policy says otherwise.
+// Rahu kaal is left to the policy, or to the CLI's --rahukaal
+var DEFAULT_AVOID = []KaalType{Yamaganda, Gulika}
+
+// Amrit, Shubh and Labh only
+var StrictPolicy = Policy{
+  Name:  "strict",
+  Day:   []Chowgadhiya{Amrit, Shubh, Labh},
+  Night: []Chowgadhiya{Amrit, Shubh, Labh},
+  Avoid: DEFAULT_AVOID,
+}
+
+var IncludeChalPolicy = Policy{
+  Name:  "include-chal",
+  Day:   []Chowgadhiya{Amrit, Shubh, Labh, Chal},
+  Night: []Chowgadhiya{Amrit, Shubh, Labh, Chal},
+  Avoid: DEFAULT_AVOID,
+}
+
+var DefaultPolicy = StrictPolicy
+
+var namedPolicies = map[string]Policy{
+  "strict":       StrictPolicy,
+  "include-chal": IncludeChalPolicy,
+  "chal":         IncludeChalPolicy,
+}
+
+func (p Policy) IsShubh(period Period) bool {
+  if p.Avoids(period.Kaal) {
+    return false
+  }
+  allowed := p.Day
+  if period.Phase == Night {
+    allowed = p.Night
+  }
+  for _, c := range allowed {
+    if c == period.Chowgadhiya {
+      return true
+    }
+  }
+  return false
+}
+
+/**
+ * Whether the policy rules out the kaal. NoKaal never is
+ */
+func (p Policy) Avoids(k KaalType) bool {
+  for _, avoided := range p.Avoid {
+    if avoided == k {
+      return true
+    }
+  }
+  return p.Refuses(k)
+}
+
+/**
+ * Whether the policy rules out the kaal even
+ * during abhijit muhurat. NoKaal never is
+ */
+func (p Policy) Refuses(k KaalType) bool {
+  for _, refused := range p.Refuse {
+    if refused == k {
+      return true
+    }
+  }
+  return false
+}
+
+/**
+ * Returns a copy of the policy that avoids the kaal
+ * even during abhijit muhurat
+ */
+func (p Policy) RefusingKaal(k KaalType) Policy {
+  if p.Refuses(k) {
+    return p
+  }
+  list := []KaalType{}
+  for _, other := range ALL_KAALS {
+    if other == k || p.Refuses(other) {
+      list = append(list, other)
+    }
+  }
+  p.Name = ""
+  p.Refuse = list
+  return p
+}
+
+/**
+ * Returns a copy of the policy that does or does not avoid the kaal
+ */
+func (p Policy) AvoidingKaal(k KaalType, avoid bool) Policy {
+  if p.Avoids(k) == avoid {
+    return p
+  }
+  list, refused := []KaalType{}, []KaalType{}
+  for _, other := range ALL_KAALS {
+    if other == k && avoid || other != k && p.Avoids(other) {
+      list = append(list, other)
+    }
+    if other != k && p.Refuses(other) {
+      refused = append(refused, other)
+    }
+  }
+  p.Name = ""
+  p.Avoid = list
+  p.Refuse = refused
+  return p
+}
+
+/**
+ * Parses a policy name, or an explicit list of chowgadhiyas
+ * used for both phases ("amrit,labh"), or separate lists
+ * per phase ("day:amrit,labh|night:shubh"). A "kaal:" part
+ * lists the kaals to avoid instead of DEFAULT_AVOID
+ * ("strict|kaal:rahukaal,yamaganda,gulika", or "kaal:none" to
+ * ignore them all), and an "abhijit" part
+ * makes abhijit muhurat shubh ("strict|abhijit"). A "refuse:" part
+ * lists kaals avoided even then ("strict|abhijit|refuse:rahukaal")
+ */
+func ParsePolicy(value string) (Policy, error) {
+  value = strings.ToLower(strings.TrimSpace(value))
+  if value == "" {
+    return DefaultPolicy, nil
+  }
+  if policy, ok := namedPolicies[value]; ok {
+    return policy, nil
+  }
+
+  policy := Policy{Day: []Chowgadhiya{}, Night: []Chowgadhiya{}, Avoid: DEFAULT_AVOID}
+  // Only kaals given means the default chowgadhiyas
+  chowgadhiyas := false
+  for _, part := range strings.Split(value, "|") {
+    if strings.TrimSpace(part) == "abhijit" {
+      policy.Abhijit = true
+      continue
+    }
+    // Only the chowgadhiyas, so kaal: and refuse: parts
+    // count wherever they come
+    if named, ok := namedPolicies[strings.TrimSpace(part)]; ok {
+      policy.Day, policy.Night = named.Day, named.Night
+      chowgadhiyas = true
+      continue
+    }
+    pieces := strings.SplitN(part, ":", 2)
+    if len(pieces) == 2 && strings.TrimSpace(pieces[0]) == "kaal" {
+      list, err := parseKaalList(pieces[1])
+      if err != nil {
+        return Policy{}, err
+      }
+      policy.Avoid = list
+      continue
+    }
+    if len(pieces) == 2 && strings.TrimSpace(pieces[0]) == "refuse" {
+      list, err := parseKaalList(pieces[1])
+      if err != nil {
+        return Policy{}, err
+      }
+      policy.Refuse = list
+      continue
+    }
+    chowgadhiyas = true
+    if len(pieces) != 2 {
+      list, err := parseChowgadhiyaList(part)
+      if err != nil {
+        return Policy{}, err
+      }
+      policy.Day, policy.Night = list, list
+      continue
+    }
+    list, err := parseChowgadhiyaList(pieces[1])
+    if err != nil {
+      return Policy{}, err
+    }
+    switch strings.TrimSpace(pieces[0]) {
+    case "day":
+      policy.Day = list
+    case "night":
+      policy.Night = list
+    default:
+      return Policy{}, newError(ErrInvalidPolicy, "unknown phase %q", pieces[0])
+    }
+  }
+  if !chowgadhiyas {
+    policy.Day, policy.Night = DefaultPolicy.Day, DefaultPolicy.Night
+  }
+  return policy, nil
+}
+
+/**
+ * Returns the kaals in the order of ALL_KAALS
+ * whatever order they were given in
+ */
+func parseKaalList(value string) ([]KaalType, error) {
+  given := map[KaalType]bool{}
+  if strings.TrimSpace(value) != "none" {
+    for _, name := range strings.Split(value, ",") {
+      name = strings.TrimSpace(name)
+      if name == "" {
+        continue
+      }
+      k, err := ParseKaal(name)
+      if err != nil {
+        return nil, newError(ErrInvalidPolicy, "%v", err)
+      }
+      given[k] = true
+    }
+  }
+  list := []KaalType{}
+  for _, k := range ALL_KAALS {
+    if given[k] {
+      list = append(list, k)
+    }
+  }
+  return list, nil
+}
+
+func parseChowgadhiyaList(value string) ([]Chowgadhiya, error) {
+  list := []Chowgadhiya{}
+  for _, name := range strings.Split(value, ",") {
+    name = strings.TrimSpace(name)
+    if name == "" {
+      continue
+    }
+    c, err := ParseChowgadhiya(name)
+    if err != nil {
+      return nil, newError(ErrInvalidPolicy, "%v", err)
+    }
+    list = append(list, c)
+  }
+  return list, nil
+}
+
+/**
+ * The name for named policies, otherwise a string that
+ * ParsePolicy turns back into the same policy
+ */
+func (p Policy) String() string {
+  if p.Name != "" {
+    return p.Name
+  }
+  day := joinChowgadhiyas(p.Day)
+  night := joinChowgadhiyas(p.Night)
+  value := "day:" + day + "|night:" + night
+  // An empty list would read back as the default policy
+  if day == night && day != "" {
+    value = day
+  }
+  if kaals := joinKaals(p.Avoid); kaals != joinKaals(DEFAULT_AVOID) {
+    value += "|kaal:" + kaals
+  }
+  if p.Abhijit {
+    value += "|abhijit"
+  }
+  if len(p.Refuse) > 0 {
+    value += "|refuse:" + joinKaals(p.Refuse)
+  }
+  return value
+}
+
+func joinKaals(list []KaalType) string {
+  if len(list) == 0 {
+    return "none"
+  }
+  names := []string{}
+  for _, k := range list {
+    names = append(names, k.String())
+  }
+  return strings.Join(names, ",")
+}
+
+func joinChowgadhiyas(list []Chowgadhiya) string {
+  names := []string{}
+  for _, c := range list {
+    names = append(names, c.String())
+  }
+  return strings.Join(names, ",")
+}
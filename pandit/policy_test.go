@@ -0,0 +1,82 @@
+package pandit
+
+import (
+  "testing"
+)
+
+var POLICY_CASES = []struct {
+  value   string
+  day     string
+  night   string
+  avoid   string
+  abhijit bool
+  refuse  string
+  // What String gives back, empty for the value itself
+  str     string
+}{
+  {"", "amrit,shubh,labh", "amrit,shubh,labh", "yamaganda,gulika", false, "none", "strict"},
+  {"strict", "amrit,shubh,labh", "amrit,shubh,labh", "yamaganda,gulika", false, "none", ""},
+  {"chal", "amrit,shubh,labh,chal", "amrit,shubh,labh,chal", "yamaganda,gulika", false, "none", "include-chal"},
+  {"amrit,labh", "amrit,labh", "amrit,labh", "yamaganda,gulika", false, "none", ""},
+  {"day:amrit,labh|night:shubh", "amrit,labh", "shubh", "yamaganda,gulika", false, "none", ""},
+  // kaal: wins wherever it comes
+  {"strict|kaal:rahukaal", "amrit,shubh,labh", "amrit,shubh,labh", "rahukaal", false, "none", "amrit,shubh,labh|kaal:rahukaal"},
+  {"kaal:rahukaal|strict", "amrit,shubh,labh", "amrit,shubh,labh", "rahukaal", false, "none", "amrit,shubh,labh|kaal:rahukaal"},
+  {"kaal:none|include-chal", "amrit,shubh,labh,chal", "amrit,shubh,labh,chal", "none", false, "none", "amrit,shubh,labh,chal|kaal:none"},
+  {"kaal:gulika,rahukaal", "amrit,shubh,labh", "amrit,shubh,labh", "rahukaal,gulika", false, "none", "amrit,shubh,labh|kaal:rahukaal,gulika"},
+  {"abhijit|strict", "amrit,shubh,labh", "amrit,shubh,labh", "yamaganda,gulika", true, "none", "amrit,shubh,labh|abhijit"},
+  {"refuse:rahukaal|strict|abhijit", "amrit,shubh,labh", "amrit,shubh,labh", "rahukaal,yamaganda,gulika", true, "rahukaal", "amrit,shubh,labh|abhijit|refuse:rahukaal"},
+  // Nothing shubh at all has to read back as nothing, not as strict
+  {"day:|night:", "", "", "yamaganda,gulika", false, "none", ""},
+  {"day:|night:shubh", "", "shubh", "yamaganda,gulika", false, "none", ""},
+}
+
+func TestParsePolicy(t *testing.T) {
+  for _, test := range POLICY_CASES {
+    policy, err := ParsePolicy(test.value)
+    if err != nil {
+      t.Errorf("%q: %v", test.value, err)
+      continue
+    }
+    avoid := []KaalType{}
+    for _, k := range ALL_KAALS {
+      if policy.Avoids(k) {
+        avoid = append(avoid, k)
+      }
+    }
+    got := []string{joinChowgadhiyas(policy.Day), joinChowgadhiyas(policy.Night), joinKaals(avoid), joinKaals(policy.Refuse)}
+    want := []string{test.day, test.night, test.avoid, test.refuse}
+    for i, name := range []string{"day", "night", "avoided kaals", "refused kaals"} {
+      if got[i] != want[i] {
+        t.Errorf("%q: %s are %q, expected %q", test.value, name, got[i], want[i])
+      }
+    }
+    if policy.Abhijit != test.abhijit {
+      t.Errorf("%q: abhijit is %v, expected %v", test.value, policy.Abhijit, test.abhijit)
+    }
+
+    str := test.str
+    if str == "" {
+      str = test.value
+    }
+    if policy.String() != str {
+      t.Errorf("%q: String gives %q, expected %q", test.value, policy.String(), str)
+    }
+    again, err := ParsePolicy(policy.String())
+    if err != nil {
+      t.Errorf("%q: %q does not parse: %v", test.value, policy.String(), err)
+      continue
+    }
+    if again.String() != policy.String() {
+      t.Errorf("%q: %q reads back as %q", test.value, policy.String(), again.String())
+    }
+  }
+}
+
+func TestParsePolicyErrors(t *testing.T) {
+  for _, value := range []string{"amrit,sometimes", "kaal:rahukaal,noon", "refuse:dusk", "evening:amrit", "stict"} {
+    if _, err := ParsePolicy(value); Cause(err) != ErrInvalidPolicy {
+      t.Errorf("%q: expected an invalid policy, got %v", value, err)
+    }
+  }
+}
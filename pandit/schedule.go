@@ -0,0 +1,77 @@
+package pandit
+
+import (
+  "time"
+)
+
+/**
+ * Returns the 8 day and 8 night chowgadhiyas, in order,
+ * of the vedic day that starts at sunrise on date.
+ * Only the calendar date of date is used
+ */
+func (c *Calculator) Schedule(date time.Time) ([]Period, error) {
+  day, err := c.VedicDayOn(date)
+  if err != nil {
+    return nil, err
+  }
+
+  return day.Periods(), nil
+}
+
+/**
+ * Splits start..end into the 8 chowgadhiyas of the phase.
+ * The night belongs to the vaar of the preceding sunrise.
+ * Every period ends exactly where the next one starts
+ */
+func (d VedicDay) phasePeriods(phase Phase, start time.Time, end time.Time) []Period {
+  list := d.List(phase)
+
+  periods := make([]Period, 0, len(list))
+  for index, element := range list {
+    kaal := NoKaal
+    if phase == Day {
+      kaal = kaalAt(d.Vaar, index)
+    }
+    periods = append(periods, Period{
+      Chowgadhiya: element,
+      Phase:       phase,
+      Vaar:        d.Vaar,
+      Start:       boundary(start, end, index, len(list)),
+      End:         boundary(start, end, index+1, len(list)),
+      Fallback:    d.Fallback,
+      Kaal:        kaal,
+    })
+  }
+  return periods
+}
+
+/**
+ * The one place period boundaries are worked out: the index'th of
+ * n equal parts of start..end, rounded to the nearest second from
+ * start. The first and last are start and end themselves, so whole
+ * second sunrises and sunsets give whole second boundaries
+ */
+func boundary(start time.Time, end time.Time, index int, n int) time.Time {
+  if index == n {
+    return end
+  }
+  offset := time.Duration(int64(end.Sub(start)) * int64(index) / int64(n))
+  return start.Add(offset.Round(time.Second))
+}
+
+/**
+ * The period of periods that t falls in. Periods are half open,
+ * a period's end belongs to the next one
+ */
+func periodAt(periods []Period, t time.Time) (Period, bool) {
+  for _, period := range periods {
+    if !t.Before(period.Start) && t.Before(period.End) {
+      return period, true
+    }
+  }
+  return Period{}, false
+}
+
+func (p Period) Duration() time.Duration {
+  return p.End.Sub(p.Start)
+}
@@ -0,0 +1,205 @@
+package pandit
+
+import (
+  "fmt"
+  "math/rand"
+  "testing"
+  "time"
+)
+
+// Fixed, so that a failure shows up again on the next run
+const PROPERTY_SEED = 20261018
+
+// Random instants checked per engine
+const PROPERTY_INSTANTS = 2000
+
+func TestBoundaryProperties(t *testing.T) {
+  for _, engine := range []SunCalculator{NOAASunCalculator{}, KelvinsSunCalculator{}} {
+    checked, skipped := checkProperties(t, PROPERTY_INSTANTS, PROPERTY_SEED, engine)
+    t.Logf("%s: properties hold at %d random instants, %d polar ones skipped", engine.Name(), checked, skipped)
+  }
+}
+
+/**
+ * Checks the boundary model at count random instants in random
+ * gazetteer cities between 1950 and 2150: the current period
+ * contains the instant, lookups, schedules and iterators agree on
+ * it, periods are contiguous with whole second boundaries, and
+ * kaals and muhurats sit where they should. One in four cities
+ * gets another city's timezone, far from its longitude, unless the
+ * engine is kelvins.
+ * Every instant that fails is reported, with the first property it
+ * broke, as the later ones build on it. Returns how many instants
+ * passed and how many were skipped
+ */
+func checkProperties(t *testing.T, count int, seed int64, engine SunCalculator) (int, int) {
+  random := rand.New(rand.NewSource(seed))
+  from := time.Date(1950, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+  to := time.Date(2150, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+  checked, skipped := 0, 0
+  for i := 0; i < count; i++ {
+    city := CITIES[random.Intn(len(CITIES))]
+    // The kelvins backend only finds events after the zone's midnight
+    zone := city
+    if engine.Name() != "kelvins" && random.Intn(4) == 0 {
+      zone = CITIES[random.Intn(len(CITIES))]
+    }
+    timezone, err := zone.TimeLocation()
+    if err != nil {
+      t.Fatal(err)
+    }
+    location := city.Location()
+    location.Timezone = timezone
+    calculator, err := NewCalculator(location, DefaultPolicy)
+    if err != nil {
+      t.Fatal(err)
+    }
+    calculator.Engine = engine
+
+    // Whole seconds land on boundaries often enough to matter
+    at := time.Unix(from+random.Int63n(to-from), 0).In(timezone)
+    if random.Intn(4) == 0 {
+      at = at.Add(time.Duration(random.Int63n(int64(time.Second))))
+    }
+
+    err = checkInstant(calculator, at)
+    if Cause(err) == ErrNoSunrise {
+      skipped++
+      continue
+    }
+    if err != nil {
+      t.Errorf("%s: %s in %s at %s: %v", engine.Name(), city.Name, timezone, at.Format(time.RFC3339Nano), err)
+      continue
+    }
+    checked++
+  }
+  return checked, skipped
+}
+
+func checkInstant(calculator *Calculator, t time.Time) error {
+  // The engine gives the sunrise of the calendar date, whatever
+  // the zone's offset from the longitude, or on a date that has
+  // none the first one after it
+  times, err := calculator.SunTimes(t)
+  if err != nil {
+    return err
+  }
+  start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+  end := time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+  if times.Sunrise.Before(start) || !times.Sunrise.Before(end.Add(time.Hour)) {
+    return fmt.Errorf("sunrise of %s is at %s", t.Format("2006-01-02"), times.Sunrise.In(t.Location()))
+  }
+
+  period, err := calculator.Chowgadhiya(t)
+  if err != nil {
+    return err
+  }
+  if t.Before(period.Start) || !t.Before(period.End) {
+    return fmt.Errorf("current period %s %s..%s does not contain it", period.Chowgadhiya, period.Start, period.End)
+  }
+  for _, b := range []time.Time{period.Start, period.End} {
+    if b.Nanosecond() != 0 {
+      return fmt.Errorf("boundary %s is not a whole second", b.Format(time.RFC3339Nano))
+    }
+  }
+
+  // Either side of each boundary belongs to a different period
+  before, err := calculator.Chowgadhiya(period.End.Add(-time.Nanosecond))
+  if err != nil {
+    return err
+  }
+  after, err := calculator.Chowgadhiya(period.End)
+  if err != nil {
+    return err
+  }
+  if before != period {
+    return fmt.Errorf("just before the end is %s, not %s", before.Chowgadhiya, period.Chowgadhiya)
+  }
+  if !after.Start.Equal(period.End) {
+    return fmt.Errorf("%s ends at %s but %s starts at %s", period.Chowgadhiya, period.End, after.Chowgadhiya, after.Start)
+  }
+
+  it, err := calculator.Iterator(t)
+  if err != nil {
+    return err
+  }
+  if it.Period() != period {
+    return fmt.Errorf("iterator is at %s, lookup says %s", it.Period().Chowgadhiya, period.Chowgadhiya)
+  }
+  next, err := it.Next()
+  if err != nil {
+    return err
+  }
+  if next != after {
+    return fmt.Errorf("next period is %s from %s, lookup at the end says %s from %s", next.Chowgadhiya, next.Start, after.Chowgadhiya, after.Start)
+  }
+  previous, err := it.Prev()
+  if err != nil {
+    return err
+  }
+  if previous != period {
+    return fmt.Errorf("stepping back gives %s, not %s", previous.Chowgadhiya, period.Chowgadhiya)
+  }
+
+  // The schedule of the vedic day holds the same period
+  day, err := calculator.VedicDay(t)
+  if err != nil {
+    return err
+  }
+  if !day.Contains(t) || period.Vaar != day.Vaar {
+    return fmt.Errorf("vedic day %s %s..%s does not match %s of %s", day.Vaar, day.Sunrise, day.NextSunrise, period.Chowgadhiya, period.Vaar)
+  }
+  // A sunrise no calendar date was given would make it two days long
+  if length := day.NextSunrise.Sub(day.Sunrise); length > 36*time.Hour {
+    return fmt.Errorf("vedic day %s..%s lasts %s", day.Sunrise, day.NextSunrise, length)
+  }
+  // A date with two sunrises has the schedule of the first
+  schedule, err := calculator.Schedule(day.Sunrise)
+  if err != nil {
+    return err
+  }
+  if !schedule[0].Start.Equal(day.Sunrise) && !schedule[len(schedule)-1].End.Equal(day.Sunrise) {
+    return fmt.Errorf("schedule of %s runs %s..%s, vedic day %s..%s", day.Sunrise.Format("2006-01-02"), schedule[0].Start, schedule[len(schedule)-1].End, day.Sunrise, day.NextSunrise)
+  }
+  periods := day.Periods()
+  if !periods[0].Start.Equal(day.Sunrise) || !periods[len(periods)-1].End.Equal(day.NextSunrise) {
+    return fmt.Errorf("periods run %s..%s, vedic day %s..%s", periods[0].Start, periods[len(periods)-1].End, day.Sunrise, day.NextSunrise)
+  }
+  found := false
+  for i, p := range periods {
+    if i > 0 && !p.Start.Equal(periods[i-1].End) {
+      return fmt.Errorf("periods have a gap or overlap before %s", p.Start)
+    }
+    if p == period {
+      found = true
+    }
+  }
+  if !found {
+    return fmt.Errorf("vedic day of %s does not hold %s from %s", day.Sunrise.Format("2006-01-02"), period.Chowgadhiya, period.Start)
+  }
+
+  // A kaal has the boundaries of the chowgadhiya it falls on
+  if period.Kaal != NoKaal {
+    kaal := day.Kaal(period.Kaal)
+    if !kaal.Start.Equal(period.Start) || !kaal.End.Equal(period.End) {
+      return fmt.Errorf("%s runs %s..%s but falls on %s %s..%s", kaal.Kaal, kaal.Start, kaal.End, period.Chowgadhiya, period.Start, period.End)
+    }
+  }
+
+  // Abhijit muhurat stays well inside the daytime
+  if abhijit, ok := day.Abhijit(); ok {
+    if !abhijit.Start.After(day.Sunrise) || !abhijit.End.Before(day.Sunset) {
+      return fmt.Errorf("abhijit muhurat %s..%s is not within the daytime %s..%s", abhijit.Start, abhijit.End, day.Sunrise, day.Sunset)
+    }
+  }
+
+  // Brahma muhurta and the twilight sandhyas stay within the night
+  for _, m := range []MuhuratType{BrahmaMuhurta, PratahSandhya, SayamSandhya} {
+    muhurat, _ := day.Muhurat(m)
+    if muhurat.Start.Before(day.Sunset) || muhurat.End.After(day.NextSunrise) || !muhurat.Start.Before(muhurat.End) {
+      return fmt.Errorf("%s %s..%s is not within the night %s..%s", m, muhurat.Start, muhurat.End, day.Sunset, day.NextSunrise)
+    }
+  }
+  return nil
+}
@@ -0,0 +1,108 @@
+package pandit
+
+import (
+  "os"
+  "sort"
+  "strings"
+  "time"
+)
+
+// When the sun rises, sets and crosses the meridian on a calendar date
+type SunTimes struct {
+  Sunrise   time.Time
+  Sunset    time.Time
+  SolarNoon time.Time
+}
+
+/**
+ * SunCalculator is an ephemeris backend. SunTimes works out the
+ * events on the calendar date of date, as seen in date's location,
+ * at the given coordinates and under the given definition of
+ * sunrise. It fails with ErrNoSunrise when the sun does not cross
+ * the horizon that day
+ */
+type SunCalculator interface {
+  // The name ?engine= and SHUBH_ENGINE pick it by
+  Name() string
+  SunTimes(date time.Time, latitude float64, longitude float64, definition SunriseDefinition) (SunTimes, error)
+}
+
+var DefaultEngine SunCalculator = KelvinsSunCalculator{}
+
+var engines = map[string]SunCalculator{
+  "kelvins": KelvinsSunCalculator{},
+  "noaa":    NOAASunCalculator{},
+}
+
+/**
+ * Makes an engine available to ParseEngine under its name.
+ * Not safe to call while calculators are in use, register
+ * engines at startup
+ */
+func RegisterEngine(engine SunCalculator) {
+  engines[engine.Name()] = engine
+}
+
+/**
+ * Returns the registered engine with this name,
+ * or the default engine for an empty name
+ */
+func ParseEngine(name string) (SunCalculator, error) {
+  name = strings.ToLower(strings.TrimSpace(name))
+  if name == "" {
+    return DefaultEngine, nil
+  }
+  if engine, ok := engines[name]; ok {
+    return engine, nil
+  }
+  return nil, newError(ErrUnknownEngine, "%q, expected one of %s", name, strings.Join(EngineNames(), ", "))
+}
+
+// Names of the registered engines, sorted
+func EngineNames() []string {
+  names := []string{}
+  for name := range engines {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+  return names
+}
+
+/**
+ * Registers the table engine when SUN_TABLE points at a
+ * precomputed CSV file. Meant to be called once at startup
+ */
+func RegisterEnginesFromEnv() error {
+  path := os.Getenv("SUN_TABLE")
+  if path == "" {
+    return nil
+  }
+  table, err := LoadTableSunCalculator(path)
+  if err != nil {
+    return err
+  }
+  RegisterEngine(table)
+  return nil
+}
+
+/**
+ * Moves sunrise and sunset from engines that only know the standard
+ * definition to where they fall under definition. The shift is
+ * worked out with the NOAA equations
+ */
+func adjustToDefinition(times SunTimes, date time.Time, latitude float64, longitude float64, definition SunriseDefinition) (SunTimes, error) {
+  if definition.IsDefault() {
+    return times, nil
+  }
+  standard, err := NOAASunCalculator{}.SunTimes(date, latitude, longitude, DefaultSunriseDefinition)
+  if err != nil {
+    return SunTimes{}, err
+  }
+  adjusted, err := NOAASunCalculator{}.SunTimes(date, latitude, longitude, definition)
+  if err != nil {
+    return SunTimes{}, err
+  }
+  times.Sunrise = times.Sunrise.Add(adjusted.Sunrise.Sub(standard.Sunrise))
+  times.Sunset = times.Sunset.Add(adjusted.Sunset.Sub(standard.Sunset))
+  return times, nil
+}
@@ -0,0 +1,171 @@
+package pandit
+
+import (
+  "fmt"
+  "math"
+  "os"
+  "strconv"
+  "strings"
+)
+
+// Which part of the sun's disc marks sunrise and sunset
+type Limb int
+
+const (
+  // The first and last gleam of the disc, what almanacs usually print
+  UpperLimb Limb = iota
+  // The middle of the disc on the horizon
+  Centre
+)
+
+var limbNames = map[Limb]string{
+  UpperLimb: "upper",
+  Centre:    "centre",
+}
+
+// How much the atmosphere lifts the sun at the horizon
+type Refraction int
+
+const (
+  // The conventional 34 arcminutes
+  StandardRefraction Refraction = iota
+  // Geometric sunrise, no atmosphere
+  NoRefraction
+)
+
+var refractionNames = map[Refraction]string{
+  StandardRefraction: "standard",
+  NoRefraction:       "none",
+}
+
+// In degrees
+const SUN_SEMIDIAMETER = 16.0 / 60
+const STANDARD_REFRACTION = 34.0 / 60
+
+// Observers above sea level see this many arcminutes
+// further below the horizon per square root metre
+const DIP_ARCMINUTES = 2.076
+
+// Highest elevation accepted, in metres
+const MAX_ELEVATION = 9000
+
+/**
+ * SunriseDefinition decides when the sun counts as risen. The zero
+ * value is the upper limb with standard refraction at sea level,
+ * the 90.833° zenith NOAA and most almanacs use
+ */
+type SunriseDefinition struct {
+  Limb       Limb
+  Refraction Refraction
+  // Observer height above the horizon in metres, sees the sun earlier
+  Elevation  float64
+}
+
+var DefaultSunriseDefinition = SunriseDefinition{}
+
+/**
+ * The sun's zenith distance in degrees at sunrise and sunset
+ */
+func (d SunriseDefinition) Zenith() float64 {
+  zenith := 90.0
+  if d.Limb == UpperLimb {
+    zenith += SUN_SEMIDIAMETER
+  }
+  if d.Refraction == StandardRefraction {
+    zenith += STANDARD_REFRACTION
+  }
+  if d.Elevation > 0 {
+    zenith += DIP_ARCMINUTES * math.Sqrt(d.Elevation) / 60
+  }
+  return zenith
+}
+
+func (d SunriseDefinition) IsDefault() bool {
+  return d == DefaultSunriseDefinition
+}
+
+func (d SunriseDefinition) String() string {
+  return fmt.Sprintf("%s limb, %s refraction, %vm", d.Limb, d.Refraction, d.Elevation)
+}
+
+func (l Limb) String() string {
+  if name, ok := limbNames[l]; ok {
+    return name
+  }
+  return "unknown"
+}
+
+func (r Refraction) String() string {
+  if name, ok := refractionNames[r]; ok {
+    return name
+  }
+  return "unknown"
+}
+
+/**
+ * Accepts upper or centre (or center)
+ */
+func ParseLimb(value string) (Limb, error) {
+  value = strings.ToLower(strings.TrimSpace(value))
+  if value == "" {
+    return DefaultSunriseDefinition.Limb, nil
+  }
+  if value == "center" {
+    return Centre, nil
+  }
+  for l, name := range limbNames {
+    if name == value {
+      return l, nil
+    }
+  }
+  return UpperLimb, newError(ErrInvalidSunriseDefinition, "limb %q, expected upper or centre", value)
+}
+
+/**
+ * Accepts standard or none
+ */
+func ParseRefraction(value string) (Refraction, error) {
+  value = strings.ToLower(strings.TrimSpace(value))
+  if value == "" {
+    return DefaultSunriseDefinition.Refraction, nil
+  }
+  for r, name := range refractionNames {
+    if name == value {
+      return r, nil
+    }
+  }
+  return StandardRefraction, newError(ErrInvalidSunriseDefinition, "refraction %q, expected standard or none", value)
+}
+
+/**
+ * Parses an elevation in metres, below sea level is allowed
+ * but sees the horizon the same as sea level
+ */
+func ParseElevation(value string) (float64, error) {
+  value = strings.TrimSpace(value)
+  if value == "" {
+    return DefaultSunriseDefinition.Elevation, nil
+  }
+  elevation, err := strconv.ParseFloat(value, 64)
+  if err != nil || !(elevation >= -500 && elevation <= MAX_ELEVATION) {
+    return 0, newError(ErrInvalidSunriseDefinition, "elevation %q, expected metres between -500 and %d", value, MAX_ELEVATION)
+  }
+  return elevation, nil
+}
+
+/**
+ * Reads SUNRISE_LIMB, SUNRISE_REFRACTION and ELEVATION from the
+ * environment. Meant to be called once at startup
+ */
+func SunriseDefinitionFromEnv() (SunriseDefinition, error) {
+  var d SunriseDefinition
+  var err error
+  if d.Limb, err = ParseLimb(os.Getenv("SUNRISE_LIMB")); err != nil {
+    return d, err
+  }
+  if d.Refraction, err = ParseRefraction(os.Getenv("SUNRISE_REFRACTION")); err != nil {
+    return d, err
+  }
+  d.Elevation, err = ParseElevation(os.Getenv("ELEVATION"))
+  return d, err
+}
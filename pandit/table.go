@@ -0,0 +1,179 @@
+package pandit
+
+import (
+  "encoding/csv"
+  "io"
+  "math"
+  "os"
+  "strconv"
+  "strings"
+  "time"
+)
+
+// How far a location may be from a table row and still use it
+const TABLE_TOLERANCE_DEGREES = 0.001
+
+// Columns of a sun table, solar_noon is optional
+var TABLE_COLUMNS = []string{"date", "latitude", "longitude", "sunrise", "sunset", "solar_noon"}
+
+/**
+ * Serves precomputed sun times from a CSV file, for the dates and
+ * locations it lists. Dates are local calendar dates as YYYY-MM-DD
+ * and times are RFC3339. Empty sunrise and sunset mark a day the sun
+ * does not rise or set. Extra columns are ignored. Rows hold the
+ * standard sunrise, other definitions are adjusted from it
+ */
+type TableSunCalculator struct {
+  rows map[string][]tableRow
+}
+
+type tableRow struct {
+  latitude  float64
+  longitude float64
+  // False on polar days
+  ok        bool
+  times     SunTimes
+}
+
+func (*TableSunCalculator) Name() string {
+  return "table"
+}
+
+func LoadTableSunCalculator(path string) (*TableSunCalculator, error) {
+  f, err := os.Open(path)
+  if err != nil {
+    return nil, newError(ErrInvalidTable, "%v", err)
+  }
+  defer f.Close()
+  return NewTableSunCalculator(f)
+}
+
+func NewTableSunCalculator(r io.Reader) (*TableSunCalculator, error) {
+  reader := csv.NewReader(r)
+  header, err := reader.Read()
+  if err != nil {
+    return nil, newError(ErrInvalidTable, "reading header: %v", err)
+  }
+  columns := map[string]int{}
+  for i, name := range header {
+    columns[strings.TrimSpace(name)] = i
+  }
+  for _, name := range TABLE_COLUMNS[:5] {
+    if _, ok := columns[name]; !ok {
+      return nil, newError(ErrInvalidTable, "missing column %q", name)
+    }
+  }
+
+  table := &TableSunCalculator{rows: map[string][]tableRow{}}
+  for line := 2; ; line++ {
+    record, err := reader.Read()
+    if err == io.EOF {
+      break
+    }
+    if err != nil {
+      return nil, newError(ErrInvalidTable, "%v", err)
+    }
+    date, row, err := parseTableRow(record, columns)
+    if err != nil {
+      return nil, newError(ErrInvalidTable, "line %d: %v", line, err)
+    }
+    table.rows[date] = append(table.rows[date], row)
+  }
+  return table, nil
+}
+
+func parseTableRow(record []string, columns map[string]int) (string, tableRow, error) {
+  var row tableRow
+  field := func(name string) string {
+    if i, ok := columns[name]; ok && i < len(record) {
+      return strings.TrimSpace(record[i])
+    }
+    return ""
+  }
+
+  date := field("date")
+  if _, err := time.Parse("2006-01-02", date); err != nil {
+    return date, row, err
+  }
+  location, err := ParseLocation(field("latitude"), field("longitude"))
+  if err != nil {
+    return date, row, err
+  }
+  row.latitude, row.longitude = location.Latitude, location.Longitude
+
+  if field("sunrise") == "" && field("sunset") == "" {
+    return date, row, nil
+  }
+  if row.times.Sunrise, err = time.Parse(time.RFC3339, field("sunrise")); err != nil {
+    return date, row, err
+  }
+  if row.times.Sunset, err = time.Parse(time.RFC3339, field("sunset")); err != nil {
+    return date, row, err
+  }
+  row.times.SolarNoon = row.times.Sunrise.Add(row.times.Sunset.Sub(row.times.Sunrise) / 2)
+  if noon := field("solar_noon"); noon != "" {
+    if row.times.SolarNoon, err = time.Parse(time.RFC3339, noon); err != nil {
+      return date, row, err
+    }
+  }
+  row.ok = true
+  return date, row, nil
+}
+
+func (table *TableSunCalculator) SunTimes(t time.Time, latitude float64, longitude float64, definition SunriseDefinition) (SunTimes, error) {
+  date := t.Format("2006-01-02")
+  for _, row := range table.rows[date] {
+    if math.Abs(row.latitude-latitude) > TABLE_TOLERANCE_DEGREES || math.Abs(row.longitude-longitude) > TABLE_TOLERANCE_DEGREES {
+      continue
+    }
+    if !row.ok {
+      return SunTimes{}, newError(ErrNoSunrise, "latitude %v on %s", latitude, date)
+    }
+    return adjustToDefinition(row.times, t, latitude, longitude, definition)
+  }
+  return SunTimes{}, newError(ErrNotInTable, "no row for %s at %v, %v", date, latitude, longitude)
+}
+
+/**
+ * Writes a table the table engine can load, with the standard sun
+ * times engine gives at location for every date from..to, inclusive.
+ * Dates are taken in from's timezone
+ */
+func WriteSunTable(w io.Writer, engine SunCalculator, location Location, from time.Time, to time.Time) error {
+  writer := csv.NewWriter(w)
+  if err := writer.Write(TABLE_COLUMNS); err != nil {
+    return err
+  }
+
+  for date := midnight(from); !date.After(to); date = date.AddDate(0, 0, 1) {
+    record, err := SunTableRecord(engine, location, date)
+    if err != nil {
+      return err
+    }
+    if err := writer.Write(record); err != nil {
+      return err
+    }
+  }
+  writer.Flush()
+  return writer.Error()
+}
+
+/**
+ * The TABLE_COLUMNS of date: the standard sun times engine gives
+ * at location, whatever definition or polar fallback is in use
+ * elsewhere, with empty times when the sun does not rise or set
+ */
+func SunTableRecord(engine SunCalculator, location Location, date time.Time) ([]string, error) {
+  latitude := strconv.FormatFloat(location.Latitude, 'f', -1, 64)
+  longitude := strconv.FormatFloat(location.Longitude, 'f', -1, 64)
+  record := []string{date.Format("2006-01-02"), latitude, longitude, "", "", ""}
+  times, err := engine.SunTimes(date, location.Latitude, location.Longitude, DefaultSunriseDefinition)
+  if err == nil {
+    record[3] = times.Sunrise.In(date.Location()).Format(time.RFC3339)
+    record[4] = times.Sunset.In(date.Location()).Format(time.RFC3339)
+    record[5] = times.SolarNoon.In(date.Location()).Format(time.RFC3339)
+  } else if Cause(err) != ErrNoSunrise {
+    return nil, err
+  }
+  return record, nil
+}
@@ -0,0 +1,120 @@
+package pandit
+
+import (
+  "testing"
+  "time"
+)
+
+/**
+ * The vedic day starting on date in city. The offsets are the ones
+ * in force at its sunrise and at the next sunrise, which differ when
+ * DST starts or ends in its night, and the sunrise clock times have
+ * to fall in the hours given
+ */
+type timezoneCase struct {
+  city          string
+  date          string
+  sunriseOffset string
+  nextOffset    string
+  sunriseHours  [2]int
+  nextHours     [2]int
+}
+
+var TIMEZONE_CASES = []timezoneCase{
+  // Half hour and three quarter hour offsets
+  {"Kolkata", "2026-01-14", "+05:30", "+05:30", [2]int{6, 7}, [2]int{6, 7}},
+  {"Kathmandu", "2026-01-14", "+05:45", "+05:45", [2]int{6, 8}, [2]int{6, 8}},
+  // DST starts on 03-08 and ends on 11-01 before sunrise
+  {"New York", "2026-03-07", "-05:00", "-04:00", [2]int{6, 7}, [2]int{7, 8}},
+  {"New York", "2026-10-31", "-04:00", "-05:00", [2]int{7, 8}, [2]int{6, 7}},
+  // Starts on 03-29 and ends on 10-25
+  {"London", "2026-03-28", "+00:00", "+01:00", [2]int{5, 6}, [2]int{6, 7}},
+  {"London", "2026-10-24", "+01:00", "+00:00", [2]int{7, 8}, [2]int{6, 7}},
+  // Ends on 04-05 and starts on 09-27, in the southern hemisphere
+  {"Auckland", "2026-04-04", "+13:00", "+12:00", [2]int{7, 8}, [2]int{6, 7}},
+  {"Auckland", "2026-09-26", "+12:00", "+13:00", [2]int{6, 7}, [2]int{7, 8}},
+  // A day ahead of their longitude
+  {"Apia", "2026-01-14", "+13:00", "+13:00", [2]int{6, 7}, [2]int{6, 7}},
+  {"Kiritimati", "2026-01-14", "+14:00", "+14:00", [2]int{6, 7}, [2]int{6, 7}},
+}
+
+func TestTimezones(t *testing.T) {
+  for _, engine := range []SunCalculator{NOAASunCalculator{}, KelvinsSunCalculator{}} {
+    for _, test := range TIMEZONE_CASES {
+      checkTimezoneCase(t, test, engine)
+    }
+  }
+}
+
+/**
+ * Reports every way the case's vedic day is off,
+ * prefixed with the engine, city and date
+ */
+func checkTimezoneCase(t *testing.T, test timezoneCase, engine SunCalculator) {
+  name := engine.Name() + ": " + test.city + " on " + test.date
+  calculator, timezone := cityCalculator(t, test.city, engine)
+  date, err := time.ParseInLocation("2006-01-02", test.date, timezone)
+  if err != nil {
+    t.Fatal(err)
+  }
+  day, err := calculator.VedicDayOn(date)
+  if err != nil {
+    t.Errorf("%s: %v", name, err)
+    return
+  }
+
+  checks := []struct {
+    name   string
+    t      time.Time
+    date   time.Time
+    offset string
+    hours  [2]int
+  }{
+    {"sunrise", day.Sunrise, date, test.sunriseOffset, test.sunriseHours},
+    {"next sunrise", day.NextSunrise, date.AddDate(0, 0, 1), test.nextOffset, test.nextHours},
+  }
+  for _, check := range checks {
+    if offset := check.t.Format("-07:00"); offset != check.offset {
+      t.Errorf("%s: %s at %s is at offset %s, expected %s", name, check.name, check.t, offset, check.offset)
+    }
+    if check.t.Format("2006-01-02") != check.date.Format("2006-01-02") {
+      t.Errorf("%s: %s at %s is not on %s", name, check.name, check.t, check.date.Format("2006-01-02"))
+    }
+    if hour := check.t.Hour(); hour < check.hours[0] || hour >= check.hours[1] {
+      t.Errorf("%s: %s at %s is not between %02d:00 and %02d:00", name, check.name, check.t.Format("15:04:05"), check.hours[0], check.hours[1])
+    }
+  }
+
+  // Sunrise to sunrise is a solar day whatever the clocks do
+  if length := day.NextSunrise.Sub(day.Sunrise); length < 24*time.Hour-10*time.Minute || length > 24*time.Hour+10*time.Minute {
+    t.Errorf("%s: vedic day lasts %s", name, length)
+  }
+
+  // Each phase splits into 8 equal real durations, the clock change
+  // in the night makes no period an hour longer or shorter
+  periods := day.Periods()
+  for i, period := range periods {
+    start, end := day.Sunrise, day.Sunset
+    if period.Phase == Night {
+      start, end = day.Sunset, day.NextSunrise
+    }
+    want := end.Sub(start) / 8
+    if diff := period.Duration() - want; diff < -time.Second || diff > time.Second {
+      t.Errorf("%s: %s %s lasts %s, expected %s", name, period.Phase, period.Chowgadhiya, period.Duration(), want)
+    }
+    if i > 0 && !period.Start.Equal(periods[i-1].End) {
+      t.Errorf("%s: gap before %s %s", name, period.Phase, period.Chowgadhiya)
+    }
+
+    // A lookup in the middle of the period finds it
+    middle := period.Start.Add(period.Duration() / 2)
+    current, err := calculator.Chowgadhiya(middle)
+    if err != nil {
+      t.Errorf("%s: %v", name, err)
+      continue
+    }
+    if current != period {
+      t.Errorf("%s: lookup at %s gives %s %s, expected %s %s", name, middle, current.Phase, current.Chowgadhiya, period.Phase, period.Chowgadhiya)
+    }
+  }
+}
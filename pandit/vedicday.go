@@ -0,0 +1,243 @@
+package pandit
+
+import (
+  "time"
+)
+
+/**
+ * Vaar is the weekday of a vedic day. It comes from the weekday
+ * of the sunrise that starts it, so the hours between midnight
+ * and sunrise still belong to the previous calendar day's vaar
+ */
+type Vaar time.Weekday
+
+const (
+  Ravivaar Vaar = iota
+  Somvaar
+  Mangalvaar
+  Budhvaar
+  Guruvaar
+  Shukravaar
+  Shanivaar
+)
+
+var vaarNames = map[Vaar]string{
+  Ravivaar:   "ravivaar",
+  Somvaar:    "somvaar",
+  Mangalvaar: "mangalvaar",
+  Budhvaar:   "budhvaar",
+  Guruvaar:   "guruvaar",
+  Shukravaar: "shukravaar",
+  Shanivaar:  "shanivaar",
+}
+
+func (v Vaar) String() string {
+  if name, ok := vaarNames[v]; ok {
+    return name
+  }
+  return "unknown"
+}
+
+func (v Vaar) Weekday() time.Weekday {
+  return time.Weekday(v)
+}
+
+/**
+ * VedicDay runs from one sunrise to the next. All chowgadhiya
+ * tables are looked up through it, by its vaar
+ */
+type VedicDay struct {
+  Vaar        Vaar
+  Sunrise     time.Time
+  Sunset      time.Time
+  NextSunrise time.Time
+  // Between sunrise and sunset, zero when not known
+  SolarNoon   time.Time
+  // Set when either sunrise needed a polar fallback
+  Fallback    PolarFallback
+}
+
+func newVedicDay(today solarDate, tomorrow solarDate) VedicDay {
+  return VedicDay{
+    Vaar:        Vaar(today.sunrise.Weekday()),
+    Sunrise:     today.sunrise,
+    Sunset:      today.sunset,
+    NextSunrise: tomorrow.sunrise,
+    SolarNoon:   today.solarNoon,
+    Fallback:    combineFallbacks(today.fallback, tomorrow.fallback),
+  }
+}
+
+/**
+ * The chowgadhiyas of the phase in order, from CHOWGADHIYA_LIST
+ */
+func (d VedicDay) List(phase Phase) []Chowgadhiya {
+  return ListFromWeekday(d.Vaar.Weekday(), phase)
+}
+
+/**
+ * All 16 periods from sunrise to the next sunrise
+ */
+func (d VedicDay) Periods() []Period {
+  periods := d.phasePeriods(Day, d.Sunrise, d.Sunset)
+  return append(periods, d.phasePeriods(Night, d.Sunset, d.NextSunrise)...)
+}
+
+func (d VedicDay) Contains(t time.Time) bool {
+  return !t.Before(d.Sunrise) && t.Before(d.NextSunrise)
+}
+
+/**
+ * Returns the vedic day that t falls in
+ */
+func (c *Calculator) VedicDay(t time.Time) (VedicDay, error) {
+  today, tomorrow, err := c.vedicDates(t)
+  if err != nil {
+    return VedicDay{}, err
+  }
+  return newVedicDay(today, tomorrow), nil
+}
+
+/**
+ * Returns the vedic day starting at sunrise on date.
+ * Only the calendar date of date is used
+ */
+func (c *Calculator) VedicDayOn(date time.Time) (VedicDay, error) {
+  // The sunrise of the calendar date, which need not come before
+  // noon in a zone far from the longitude
+  today, err := c.solarDate(startOfDay(date.Year(), date.Month(), date.Day(), c.timezone(date)))
+  if err != nil {
+    return VedicDay{}, err
+  }
+  tomorrow, err := c.solarDateAfter(today, 1)
+  if err != nil {
+    return VedicDay{}, err
+  }
+  if err := checkVedicDay(today, tomorrow); err != nil {
+    return VedicDay{}, err
+  }
+  return newVedicDay(today, tomorrow), nil
+}
+
+// Sunrise, sunset and solar noon of a single calendar date
+type solarDate struct {
+  // Midnight starting the date, in the calculator's timezone.
+  // Steps go by this rather than by the sunrise, which an engine
+  // may put on either side of the date's own midnight
+  date      time.Time
+  sunrise   time.Time
+  sunset    time.Time
+  solarNoon time.Time
+  fallback  PolarFallback
+}
+
+func (c *Calculator) solarDate(t time.Time) (solarDate, error) {
+  date := midnight(t.In(c.timezone(t)))
+  times, fallback, err := c.sunriseSunset(date)
+  return solarDate{date, times.Sunrise, times.Sunset, times.SolarNoon, fallback}, err
+}
+
+/**
+ * The solar date a calendar day after d, or before it when days
+ * is -1. In a zone far from the longitude the sunrise crosses
+ * midnight twice a year: a date can then have none of its own and
+ * be given the next date's, which is stepped over, or have two and
+ * give only one, and the other is looked up on its own
+ */
+func (c *Calculator) solarDateAfter(d solarDate, days int) (solarDate, error) {
+  next, err := c.solarDate(addDays(d.date, days))
+  if err != nil {
+    return next, err
+  }
+  if (days > 0) != next.sunrise.After(d.sunrise) {
+    return c.solarDate(addDays(next.date, days))
+  }
+  if gap := next.sunrise.Sub(d.sunrise); gap > 36*time.Hour || gap < -36*time.Hour {
+    return c.missedSolarDate(d, days)
+  }
+  return next, nil
+}
+
+/**
+ * The sunrise a day after (or before) d's that no calendar date
+ * of the calculator's timezone was given. It is worked out on the
+ * date it falls on in a zone where it comes around noon, which has
+ * no other. Keeps d's date, so stepping on from it still works
+ */
+func (c *Calculator) missedSolarDate(d solarDate, days int) (solarDate, error) {
+  around := d.sunrise.Add(time.Duration(days) * 24 * time.Hour).UTC()
+  offset := 12*60*60 - (around.Hour()*60*60 + around.Minute()*60 + around.Second())
+  if offset > 12*60*60 {
+    offset -= 24 * 60 * 60
+  }
+  date := midnight(around.In(time.FixedZone("", offset)))
+
+  times, err := c.engine().SunTimes(date, c.Location.Latitude, c.Location.Longitude, c.Definition)
+  if err != nil {
+    return solarDate{}, err
+  }
+  loc := c.timezone(d.date)
+  debug("Missed sunrise", times.Sunrise.In(loc))
+  return solarDate{d.date, times.Sunrise.In(loc), times.Sunset.In(loc), times.SolarNoon.In(loc), FallbackNone}, nil
+}
+
+/**
+ * Returns the calendar date whose sunrise starts the vedic
+ * day that now falls in, and the date after it
+ */
+func (c *Calculator) vedicDates(now time.Time) (solarDate, solarDate, error) {
+  var today, tomorrow solarDate
+
+  now = now.In(c.timezone(now))
+  today, err := c.solarDate(now)
+  if err != nil {
+    return today, tomorrow, err
+  }
+
+  // Sun has not risen yet
+  // So check the sunrise for yesterday
+  if now.Before(today.sunrise) {
+    debug("Sun is not yet up, go back to bed")
+    tomorrow = today
+    today, err = c.solarDateAfter(tomorrow, -1)
+  } else {
+    debug("Sun is up, rise and shine")
+    // Calculate the sunrise time for tomorrow
+    tomorrow, err = c.solarDateAfter(today, 1)
+  }
+  if err != nil {
+    return today, tomorrow, err
+  }
+
+  // A missed sunrise looked up in between can be the one that
+  // starts the vedic day, or the one that ends it
+  if !now.Before(tomorrow.sunrise) {
+    today = tomorrow
+    tomorrow, err = c.solarDateAfter(today, 1)
+  } else if now.Before(today.sunrise) {
+    tomorrow = today
+    today, err = c.solarDateAfter(tomorrow, -1)
+  }
+  if err != nil {
+    return today, tomorrow, err
+  }
+
+  // Now we have a definite sunrise time for the "vedic day"
+
+  if err := checkVedicDay(today, tomorrow); err != nil {
+    return today, tomorrow, err
+  }
+
+  debug("Sunrise:", today.sunrise)
+  debug("Sunset:", today.sunset)
+  debug("Next sunrise:", tomorrow.sunrise)
+
+  return today, tomorrow, nil
+}
+
+func checkVedicDay(today solarDate, tomorrow solarDate) error {
+  if !(today.sunrise.Before(today.sunset) && today.sunset.Before(tomorrow.sunrise)) {
+    return newError(ErrInconsistentVedicDay, "sunrise %v, sunset %v, next sunrise %v", today.sunrise, today.sunset, tomorrow.sunrise)
+  }
+  return nil
+}
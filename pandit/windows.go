@@ -0,0 +1,146 @@
+package pandit
+
+import (
+  "time"
+)
+
+// How far NextWindows looks before giving up
+const MAX_LOOKAHEAD_DAYS = 366
+
+// A stretch of shubh time made of one or more chowgadhiyas
+type Window struct {
+  Start        time.Time
+  End          time.Time
+  Chowgadhiyas []Chowgadhiya
+}
+
+/**
+ * The part of period that is shubh: all of it when the policy
+ * allows its chowgadhiya, or else the abhijit muhurat of day that
+ * falls in it, when the policy counts abhijit and does not refuse
+ * the period's kaal. ok is false when none of it is
+ */
+func (c *Calculator) shubhPart(day VedicDay, period Period) (part Period, ok bool) {
+  if c.Policy.IsShubh(period) {
+    return period, true
+  }
+  if !c.Policy.Abhijit || c.Policy.Refuses(period.Kaal) {
+    return Period{}, false
+  }
+  abhijit, ok := day.Abhijit()
+  if !ok || !abhijit.Start.Before(period.End) || !period.Start.Before(abhijit.End) {
+    return Period{}, false
+  }
+  if abhijit.Start.After(period.Start) {
+    period.Start = abhijit.Start
+  }
+  if abhijit.End.Before(period.End) {
+    period.End = abhijit.End
+  }
+  return period, true
+}
+
+/**
+ * Returns the next n shubh windows that have not ended by t,
+ * in chronological order. The window in progress at t, if any,
+ * comes first. With merge, back to back shubh chowgadhiyas
+ * (say Labh followed by Amrit) are joined into a single window.
+ * Abhijit muhurat counts when the policy says so, and makes a
+ * window of its own or stretches the one next to it
+ */
+func (c *Calculator) NextWindows(t time.Time, n int, merge bool) ([]Window, error) {
+  windows := []Window{}
+  if n < 1 {
+    return windows, nil
+  }
+
+  it, err := c.Iterator(t)
+  if err != nil {
+    return nil, err
+  }
+
+  limit := t.AddDate(0, 0, MAX_LOOKAHEAD_DAYS)
+  for it.Period().Start.Before(limit) {
+    // Abhijit muhurat may be over by t in the period t falls in
+    part, ok := it.ShubhPart()
+    last := len(windows) - 1
+    if !ok || !part.End.After(t) {
+      // The next shubh period can't be merged into the last window
+      // so we are done once we have enough
+      if len(windows) == n {
+        break
+      }
+    } else if merge && last >= 0 && windows[last].End.Equal(part.Start) {
+      windows[last].End = part.End
+      windows[last].Chowgadhiyas = append(windows[last].Chowgadhiyas, part.Chowgadhiya)
+    } else {
+      if len(windows) == n {
+        break
+      }
+      windows = append(windows, Window{
+        Start:        part.Start,
+        End:          part.End,
+        Chowgadhiyas: []Chowgadhiya{part.Chowgadhiya},
+      })
+    }
+
+    if _, err := it.Next(); err != nil {
+      return nil, err
+    }
+  }
+  return windows, nil
+}
+
+/**
+ * Returns the run of back to back shubh periods that t falls in,
+ * abhijit muhurat included when the policy says so.
+ * ok is false when t itself is not shubh
+ */
+func (c *Calculator) CurrentWindow(t time.Time) (window Window, ok bool, err error) {
+  it, err := c.Iterator(t)
+  if err != nil {
+    return Window{}, false, err
+  }
+
+  current, ok := it.ShubhPart()
+  if !ok || t.Before(current.Start) || !t.Before(current.End) {
+    return Window{}, false, nil
+  }
+
+  window = Window{Start: current.Start, End: current.End, Chowgadhiyas: []Chowgadhiya{current.Chowgadhiya}}
+
+  limit := t.AddDate(0, 0, MAX_LOOKAHEAD_DAYS)
+  for window.End.Before(limit) {
+    if _, err := it.Next(); err != nil {
+      return Window{}, false, err
+    }
+    // A window ending with abhijit muhurat stops short of the period
+    part, ok := it.ShubhPart()
+    if !ok || !part.Start.Equal(window.End) {
+      break
+    }
+    window.End = part.End
+    window.Chowgadhiyas = append(window.Chowgadhiyas, part.Chowgadhiya)
+  }
+
+  // Start over from t and walk the other way
+  it, err = c.Iterator(t)
+  if err != nil {
+    return Window{}, false, err
+  }
+
+  limit = t.AddDate(0, 0, -MAX_LOOKAHEAD_DAYS)
+  for window.Start.After(limit) {
+    if _, err := it.Prev(); err != nil {
+      return Window{}, false, err
+    }
+    part, ok := it.ShubhPart()
+    if !ok || !part.End.Equal(window.Start) {
+      break
+    }
+    window.Start = part.Start
+    window.Chowgadhiyas = append([]Chowgadhiya{part.Chowgadhiya}, window.Chowgadhiyas...)
+  }
+
+  return window, true, nil
+}
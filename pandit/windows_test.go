@@ -0,0 +1,138 @@
+package pandit
+
+import (
+  "testing"
+  "time"
+)
+
+/**
+ * With abhijit muhurat in the policy, IsShubh, CurrentWindow,
+ * NextShubh and NextWindows give the same answer at every instant
+ * of a week, budhvaar and all
+ */
+func TestAbhijitWindows(t *testing.T) {
+  policy, err := ParsePolicy("strict|abhijit")
+  if err != nil {
+    t.Fatal(err)
+  }
+  ist := time.FixedZone("IST", 19800)
+  calculator, err := NewCalculator(Location{Latitude: 26.7880, Longitude: 82.1986, Timezone: ist}, policy)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  start := time.Date(2026, 10, 18, 0, 0, 0, 0, ist)
+  for at := start; at.Before(start.AddDate(0, 0, 7)); at = at.Add(11 * time.Minute) {
+    shubh, err := calculator.IsShubh(at)
+    if err != nil {
+      t.Fatal(err)
+    }
+    window, ok, err := calculator.CurrentWindow(at)
+    if err != nil {
+      t.Fatal(err)
+    }
+    if ok != shubh || ok && (at.Before(window.Start) || !at.Before(window.End)) {
+      t.Errorf("%s: IsShubh is %v, current window %v %s..%s", at, shubh, ok, window.Start, window.End)
+    }
+
+    next, err := calculator.NextShubh(at)
+    if err != nil {
+      t.Fatal(err)
+    }
+    windows, err := calculator.NextWindows(at, 2, true)
+    if err != nil {
+      t.Fatal(err)
+    }
+    // The first window is the one in progress, if any, from the
+    // period t falls in, and the next shubh time starts it or the
+    // one after
+    first := windows[0]
+    if ok && !first.End.Equal(window.End) {
+      t.Errorf("%s: first window ends at %s, current window at %s", at, first.End, window.End)
+    }
+    if !ok && !next.Start.Equal(first.Start) {
+      t.Errorf("%s: next shubh at %s, first window at %s", at, next.Start, first.Start)
+    }
+    if ok && next.Start.After(window.End) && !next.Start.Equal(windows[1].Start) {
+      t.Errorf("%s: next shubh at %s, window after the current one at %s", at, next.Start, windows[1].Start)
+    }
+  }
+}
+
+/**
+ * Rahu kaal takes the 4th eighth of shukravaar's daytime and
+ * overlaps the first half of abhijit muhurat. Refusing it wins
+ * over abhijit, avoiding it alone does not
+ */
+func TestAbhijitRefusedKaal(t *testing.T) {
+  ist := time.FixedZone("IST", 19800)
+  calculator, err := NewCalculator(Location{Latitude: 26.7880, Longitude: 82.1986, Timezone: ist}, DefaultPolicy)
+  if err != nil {
+    t.Fatal(err)
+  }
+  // Shukravaar, rahu kaal 10:20-11:45 and abhijit 11:22-12:08
+  date := time.Date(2026, 10, 23, 0, 0, 0, 0, ist)
+  day, err := calculator.VedicDayOn(date)
+  if err != nil {
+    t.Fatal(err)
+  }
+  rahuKaal := day.Kaal(RahuKaal)
+  abhijit, ok := day.Abhijit()
+  if day.Vaar != Shukravaar || !ok || !abhijit.Start.Before(rahuKaal.End) || !rahuKaal.End.Before(abhijit.End) {
+    t.Fatalf("%s: rahu kaal %s..%s does not overlap abhijit %s..%s", day.Vaar, rahuKaal.Start, rahuKaal.End, abhijit.Start, abhijit.End)
+  }
+  overlap := abhijit.Start.Add(time.Minute)
+  after := rahuKaal.End.Add(time.Minute)
+
+  for _, value := range []string{"strict|abhijit|kaal:rahukaal", "strict|abhijit|refuse:rahukaal"} {
+    calculator.Policy, err = ParsePolicy(value)
+    if err != nil {
+      t.Fatal(err)
+    }
+    refused := calculator.Policy.Refuses(RahuKaal)
+
+    shubh, err := calculator.IsShubh(overlap)
+    if err != nil {
+      t.Fatal(err)
+    }
+    if shubh == refused {
+      t.Errorf("%s: IsShubh in rahu kaal and abhijit is %v", value, shubh)
+    }
+    _, ok, err := calculator.CurrentWindow(overlap)
+    if err != nil {
+      t.Fatal(err)
+    }
+    if ok != shubh {
+      t.Errorf("%s: current window %v, IsShubh %v", value, ok, shubh)
+    }
+
+    // Refused, abhijit only counts from the end of rahu kaal
+    next, err := calculator.NextShubh(rahuKaal.Start)
+    if err != nil {
+      t.Fatal(err)
+    }
+    want := abhijit.Start
+    if refused {
+      want = rahuKaal.End
+    }
+    if !next.Start.Equal(want) {
+      t.Errorf("%s: next shubh after %s at %s, expected %s", value, rahuKaal.Start, next.Start, want)
+    }
+    if shubh, err := calculator.IsShubh(after); err != nil || !shubh {
+      t.Errorf("%s: IsShubh in abhijit after rahu kaal is %v, %v", value, shubh, err)
+    }
+  }
+
+  // What the CLI's --rahukaal refuse does to strict|abhijit
+  policy, err := ParsePolicy("strict|abhijit")
+  if err != nil {
+    t.Fatal(err)
+  }
+  calculator.Policy = policy.RefusingKaal(RahuKaal)
+  if shubh, err := calculator.IsShubh(overlap); err != nil || shubh {
+    t.Errorf("%s: IsShubh in rahu kaal and abhijit is %v, %v", calculator.Policy, shubh, err)
+  }
+  if value := calculator.Policy.String(); value != "amrit,shubh,labh|abhijit|refuse:rahukaal" {
+    t.Errorf("refusing rahu kaal gives %q", value)
+  }
+}
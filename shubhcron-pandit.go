@@ -4,117 +4,424 @@ import (
   "encoding/json"
   "fmt"
   "log"
+  "math"
   "net/http"
   "os"
+  "strconv"
   "time"
+
+  "shubhcron-pandit/pandit"
 )
 
 type Response struct {
-  IsShubh   bool
-  NextShubh int64
-  Current   string              `json:"current"`
-  List      ChowgadhiyaTimeList `json:"list"`
+  // The instant everything else was evaluated at
+  At               int64               `json:"at"`
+  IsShubh          bool
+  // Start of the next shubh period, 0 when the policy
+  // finds none within a year
+  NextShubh        int64
+  Policy           string              `json:"policy"`
+  Engine           string              `json:"engine"`
+  Definition       DefinitionResponse  `json:"definition"`
+  Current          string              `json:"current"`
+  // Of the vedic day we are in, which starts at sunrise
+  Vaar             string              `json:"vaar"`
+  CurrentStart     int64               `json:"currentStart"`
+  CurrentEnd       int64               `json:"currentEnd"`
+  RemainingSeconds int64               `json:"remainingSeconds"`
+  // The kaal the current chowgadhiya coincides with, if any
+  CurrentKaal      string              `json:"currentKaal,omitempty"`
+  // End of the merged run of shubh periods we are in, absent when not shubh
+  CurrentWindowEnd *int64              `json:"currentWindowEnd,omitempty"`
+  // How the current vedic day was worked out past the polar circles
+  Fallback         string              `json:"fallback,omitempty"`
+  // The rahu kaal we are in, or else the next one
+  RahuKaal         KaalResponse        `json:"rahuKaal"`
+  // The abhijit muhurat we are in, or else the next one
+  Abhijit          MuhuratResponse     `json:"abhijit"`
+  List             ChowgadhiyaTimeList `json:"list"`
+  Windows          []WindowResponse    `json:"windows"`
 }
 
 type ChowgadhiyaTimeList map[string]int64
 
-var chowgadhiyaToStringMap = map[Chowgadhiya]string{
-  Chal:  "chal",
-  Amrit: "amrit",
-  Kaal:  "kaal",
-  Labh:  "labh",
-  Rog:   "rog",
-  Shubh: "shubh",
-  Udveg: "udveg",
+type WindowResponse struct {
+  Start int64    `json:"start"`
+  End   int64    `json:"end"`
+  Names []string `json:"names"`
+}
+
+// The sunrise definition in effect, zenith in degrees
+type DefinitionResponse struct {
+  Limb       string  `json:"limb"`
+  Refraction string  `json:"refraction"`
+  Elevation  float64 `json:"elevation"`
+  Zenith     float64 `json:"zenith"`
 }
 
-func otherPhase(p Phase) Phase {
-  if p == Day {
-    return Night
+func newDefinitionResponse(d pandit.SunriseDefinition) DefinitionResponse {
+  return DefinitionResponse{
+    Limb:       d.Limb.String(),
+    Refraction: d.Refraction.String(),
+    Elevation:  d.Elevation,
+    Zenith:     math.Floor(d.Zenith()*10000+0.5) / 10000,
   }
-  return Day
 }
 
-func getSoonestShubhTime(list map[string]int64) int64 {
-  // Arbitrary minimum, one more digit than are in timestamps at the moment
-  // This means this script will stop working in the year 2286
-  // Hope I learn how to write Golang before that
-  min := int64(10000000000)
-  for _, value := range list {
-    if value < min {
-      min = value
+const DEFAULT_WINDOW_COUNT = 5
+const MAX_WINDOW_COUNT = 50
+
+type ErrorResponse struct {
+  Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+  Code    string `json:"code"`
+  Message string `json:"message"`
+}
+
+// Problems with the query string itself
+type queryError struct {
+  param   string
+  message string
+}
+
+func (e *queryError) Error() string {
+  return "invalid " + e.param + ": " + e.message
+}
+
+// Configured once at startup from the environment,
+// requests get a copy with their own overrides applied
+var defaultCalculator *pandit.Calculator
+var defaultTimezone = time.Local
+
+/**
+ * Returns the calculator and timezone to use for this request, the
+ * server defaults with ?city= or ?lat=&lon=, ?tz=, ?policy=, ?polar=,
+ * ?engine= and ?limb=, ?refraction=, ?elevation= applied on top.
+ * A city brings its own timezone unless tz is also given
+ */
+func calculatorForRequest(r *http.Request) (*pandit.Calculator, *time.Location, error) {
+  query := r.URL.Query()
+  calculator := *defaultCalculator
+  timezone := defaultTimezone
+
+  lat, lon := query.Get("lat"), query.Get("lon")
+  if name := query.Get("city"); name != "" {
+    if lat != "" || lon != "" {
+      return nil, nil, &queryError{"city", "give either city or lat and lon, not both"}
+    }
+    city, err := pandit.LookupCity(name)
+    if err != nil {
+      return nil, nil, err
     }
+    calculator.Location = city.Location()
+    timezone, err = city.TimeLocation()
+    if err != nil {
+      return nil, nil, err
+    }
+  } else if lat != "" || lon != "" {
+    if lat == "" || lon == "" {
+      return nil, nil, &queryError{"lat", "lat and lon must be given together"}
+    }
+    location, err := pandit.ParseLocation(lat, lon)
+    if err != nil {
+      return nil, nil, err
+    }
+    calculator.Location = location
+  }
+
+  if value := query.Get("tz"); value != "" {
+    loc, err := time.LoadLocation(value)
+    // LoadLocation takes "Local" to mean the server's
+    // zone, which is never what a caller meant
+    if err != nil || value == "Local" {
+      return nil, nil, &queryError{"tz", fmt.Sprintf("unknown IANA timezone %q", value)}
+    }
+    timezone = loc
   }
-  return min
-}
 
-func getChowgadhiyaList(t time.Time) map[string]int64 {
-  sunrise, sunset, nextSunrise := getVedicDay(t)
+  if value := query.Get("policy"); value != "" {
+    policy, err := pandit.ParsePolicy(value)
+    if err != nil {
+      return nil, nil, err
+    }
+    calculator.Policy = policy
+  }
 
-  var baseTime time.Time
-  var nextBase time.Time
-  var phase Phase
-  var offsetInSeconds float64
+  if value := query.Get("polar"); value != "" {
+    polar, err := pandit.ParsePolarFallback(value)
+    if err != nil {
+      return nil, nil, err
+    }
+    calculator.Polar = polar
+  }
+
+  if value := query.Get("engine"); value != "" {
+    engine, err := pandit.ParseEngine(value)
+    if err != nil {
+      return nil, nil, err
+    }
+    calculator.Engine = engine
+  }
 
-  if t.Before(sunset) {
-    // Daytime
-    phase = Day
-    baseTime = sunrise
-    nextBase = sunset
-    offsetInSeconds = (sunset.Sub(sunrise) / 8).Seconds()
-  } else {
-    // Nighttime
-    phase = Night
-    baseTime = sunset
-    nextBase = nextSunrise
-    offsetInSeconds = (nextSunrise.Sub(sunset) / 8).Seconds()
-    debug("time difference:", nextSunrise.Sub(t).Hours())
+  if value := query.Get("limb"); value != "" {
+    limb, err := pandit.ParseLimb(value)
+    if err != nil {
+      return nil, nil, err
+    }
+    calculator.Definition.Limb = limb
+  }
+  if value := query.Get("refraction"); value != "" {
+    refraction, err := pandit.ParseRefraction(value)
+    if err != nil {
+      return nil, nil, err
+    }
+    calculator.Definition.Refraction = refraction
+  }
+  if value := query.Get("elevation"); value != "" {
+    elevation, err := pandit.ParseElevation(value)
+    if err != nil {
+      return nil, nil, err
+    }
+    calculator.Definition.Elevation = elevation
   }
 
-  todayList := getChowgadhiyaListFromWeekday(t.Weekday(), phase)
-  // fmt.Println(todayList)
-  nextDayList := getChowgadhiyaListFromWeekday(t.Weekday()+1, otherPhase(phase))
-  // fmt.Println(nextDayList)
+  calculator.Location.Timezone = timezone
+  return &calculator, timezone, nil
+}
+
+/**
+ * Start times of the shubh chowgadhiyas left in the current phase,
+ * or in the following phase if there are none left in this one.
+ * Abhijit muhurat, when the policy counts it and not the
+ * chowgadhiya it falls on, is listed under its own name
+ */
+func getChowgadhiyaList(calculator *pandit.Calculator, t time.Time) (map[string]int64, error) {
+  it, err := calculator.Iterator(t)
+  if err != nil {
+    return nil, err
+  }
 
   cList := make(map[string]int64)
+  phase := it.Period().Phase
+  switched := false
 
-  for index, element := range todayList {
-    delta := (float64(index) * offsetInSeconds)
-    startTime := (int64(delta) + baseTime.Unix())
-    if startTime > t.Unix() && isChowgadhiyaConsideredShubh(element) {
-      cList[chowgadhiyaToStringMap[element]] = startTime
+  for period := it.Period(); ; {
+    // Only abhijit muhurat can start after t in the period t falls in
+    if part, ok := it.ShubhPart(); ok && part.Start.After(t) {
+      if calculator.Policy.IsShubh(period) {
+        cList[period.Chowgadhiya.String()] = part.Start.Unix()
+      } else if abhijit, _ := it.VedicDay().Abhijit(); abhijit.Start.After(t) {
+        // From where it starts, though it can straddle two chowgadhiyas
+        cList[pandit.AbhijitMuhurat.String()] = abhijit.Start.Unix()
+      }
     }
-  }
 
-  if len(cList) == 0 {
-    for index, element := range nextDayList {
-      delta := (float64(index) * offsetInSeconds)
-      startTime := (int64(delta) + nextBase.Unix())
-      if startTime > t.Unix() && isChowgadhiyaConsideredShubh(element) {
-        cList[chowgadhiyaToStringMap[element]] = startTime
+    period, err = it.Next()
+    if err != nil {
+      return nil, err
+    }
+    if period.Phase != phase {
+      if len(cList) > 0 || switched {
+        break
       }
+      phase = period.Phase
+      switched = true
     }
   }
 
-  return cList
+  return cList, nil
 }
 
-func getChowgadhiyaResponse(w http.ResponseWriter, r *http.Request) {
-  now := time.Now()
-  chowgadhiya := getChowgadhiya(now)
-
-  isShubh := isShubh(now)
-  current := chowgadhiyaToStringMap[chowgadhiya]
-  list := getChowgadhiyaList(now)
-  nextShubh := getSoonestShubhTime(list)
+/**
+ * Bad input is a 400, a valid request we cannot answer
+ * (out of range dates, polar days) is a 422, and
+ * anything else means we have a bug
+ */
+func errorStatus(err error) (int, string) {
+  if _, ok := err.(*queryError); ok {
+    return http.StatusBadRequest, "invalid_query"
+  }
+  switch pandit.Cause(err) {
+  case pandit.ErrInvalidLocation:
+    return http.StatusBadRequest, "invalid_location"
+  case pandit.ErrInvalidPolicy:
+    return http.StatusBadRequest, "invalid_policy"
+  case pandit.ErrUnknownCity:
+    return http.StatusBadRequest, "unknown_city"
+  case pandit.ErrInvalidPolarFallback:
+    return http.StatusBadRequest, "invalid_polar_fallback"
+  case pandit.ErrUnknownEngine:
+    return http.StatusBadRequest, "unknown_engine"
+  case pandit.ErrInvalidSunriseDefinition:
+    return http.StatusBadRequest, "invalid_sunrise_definition"
+  case pandit.ErrUnknownMuhurat:
+    return http.StatusBadRequest, "unknown_muhurat"
+  case pandit.ErrDateOutOfRange:
+    return http.StatusUnprocessableEntity, "date_out_of_range"
+  case pandit.ErrNoSunrise:
+    return http.StatusUnprocessableEntity, "no_sunrise"
+  case pandit.ErrNotInTable:
+    return http.StatusUnprocessableEntity, "not_in_table"
+  case pandit.ErrNoMuhurat:
+    return http.StatusUnprocessableEntity, "no_muhurat"
+  case pandit.ErrNoShubhPeriod:
+    return http.StatusUnprocessableEntity, "no_shubh_period"
+  case pandit.ErrInconsistentVedicDay:
+    return http.StatusInternalServerError, "inconsistent_vedic_day"
+  }
+  return http.StatusInternalServerError, "internal_error"
+}
 
-  response := Response{isShubh, nextShubh, current, list}
+func writeError(w http.ResponseWriter, err error) {
+  status, code := errorStatus(err)
+  log.Printf("%d %s: %v", status, code, err)
+  writeJSON(w, status, ErrorResponse{ErrorBody{code, err.Error()}})
+}
 
-  fmt.Println(response)
-  jResponse, _ := json.Marshal(response)
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+  jResponse, err := json.Marshal(body)
+  if err != nil {
+    log.Println("error encoding response:", err)
+    status = http.StatusInternalServerError
+    jResponse = []byte(`{"error":{"code":"internal_error","message":"could not encode response"}}`)
+  }
   w.Header().Set("Access-Control-Allow-Origin", "*")
   w.Header().Set("Content-Type", "application/json")
-  fmt.Fprintf(w, string(jResponse))
+  w.WriteHeader(status)
+  w.Write(jResponse)
+}
+
+/**
+ * GET /chowgadhiya[?at=...]
+ * Evaluates now, or the instant given as RFC3339 or unix seconds
+ */
+func getChowgadhiyaResponse(w http.ResponseWriter, r *http.Request) {
+  calculator, timezone, err := calculatorForRequest(r)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+
+  now := time.Now().In(timezone)
+  if value := r.URL.Query().Get("at"); value != "" {
+    now, err = parseTimeParam("at", value, timezone)
+    if err != nil {
+      writeError(w, err)
+      return
+    }
+  }
+
+  period, err := calculator.Chowgadhiya(now)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+
+  // Abhijit muhurat can make it shubh whatever the chowgadhiya
+  isShubh, err := calculator.IsShubh(now)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+  current := period.Chowgadhiya.String()
+  list, err := getChowgadhiyaList(calculator, now)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+  // A policy that matches nothing still gets the rest
+  nextShubh, err := calculator.NextShubh(now)
+  if err != nil && pandit.Cause(err) != pandit.ErrNoShubhPeriod {
+    writeError(w, err)
+    return
+  }
+  var nextShubhStart int64
+  if err == nil {
+    nextShubhStart = nextShubh.Start.Unix()
+  }
+
+  query := r.URL.Query()
+  count := DEFAULT_WINDOW_COUNT
+  if value := query.Get("windows"); value != "" {
+    count, err = strconv.Atoi(value)
+    if err != nil || count < 0 || count > MAX_WINDOW_COUNT {
+      writeError(w, &queryError{"windows", fmt.Sprintf("expected a number between 0 and %d", MAX_WINDOW_COUNT)})
+      return
+    }
+  }
+  windows, err := calculator.NextWindows(now, count, query.Get("merge") == "true")
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+
+  var currentWindowEnd *int64
+  window, ok, err := calculator.CurrentWindow(now)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+  if ok {
+    end := window.End.Unix()
+    currentWindowEnd = &end
+  }
+
+  rahuKaal, err := calculator.NextKaal(pandit.RahuKaal, now)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+  abhijit, err := calculator.NextMuhurat(pandit.AbhijitMuhurat, now)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+
+  response := Response{
+    At:               now.Unix(),
+    IsShubh:          isShubh,
+    NextShubh:        nextShubhStart,
+    Policy:           calculator.Policy.String(),
+    Engine:           calculator.Engine.Name(),
+    Definition:       newDefinitionResponse(calculator.Definition),
+    Current:          current,
+    Vaar:             period.Vaar.String(),
+    CurrentStart:     period.Start.Unix(),
+    CurrentEnd:       period.End.Unix(),
+    RemainingSeconds: int64(period.End.Sub(now).Seconds()),
+    CurrentKaal:      kaalName(period.Kaal),
+    CurrentWindowEnd: currentWindowEnd,
+    RahuKaal:         newKaalResponse(rahuKaal, now),
+    Abhijit:          newMuhuratResponse(abhijit, now),
+    List:             list,
+    Windows:          newWindowResponses(windows),
+    Fallback:         fallbackName(period.Fallback),
+  }
+
+  pandit.Debug(response)
+  writeJSON(w, http.StatusOK, response)
+}
+
+// Empty when no fallback was needed, so it is left out of responses
+func fallbackName(f pandit.PolarFallback) string {
+  if f == pandit.FallbackNone {
+    return ""
+  }
+  return f.String()
+}
+
+func newWindowResponses(windows []pandit.Window) []WindowResponse {
+  responses := []WindowResponse{}
+  for _, window := range windows {
+    names := []string{}
+    for _, c := range window.Chowgadhiyas {
+      names = append(names, c.String())
+    }
+    responses = append(responses, WindowResponse{window.Start.Unix(), window.End.Unix(), names})
+  }
+  return responses
 }
 
 func determineListenAddress() (string, error) {
@@ -126,7 +433,59 @@ func determineListenAddress() (string, error) {
 }
 
 func main() {
+  location, err := pandit.LocationFromEnv()
+  if err != nil {
+    log.Fatal(err)
+  }
+  // CITY takes precedence over LATITUDE and LONGITUDE
+  if name := os.Getenv("CITY"); name != "" {
+    city, err := pandit.LookupCity(name)
+    if err != nil {
+      log.Fatal(err)
+    }
+    location = city.Location()
+    defaultTimezone, err = city.TimeLocation()
+    if err != nil {
+      log.Fatal(err)
+    }
+  }
+  policy, err := pandit.ParsePolicy(os.Getenv("SHUBH_POLICY"))
+  if err != nil {
+    log.Fatal(err)
+  }
+  defaultCalculator, err = pandit.NewCalculator(location, policy)
+  if err != nil {
+    log.Fatal(err)
+  }
+  defaultCalculator.Polar, err = pandit.ParsePolarFallback(os.Getenv("SHUBH_POLAR"))
+  if err != nil {
+    log.Fatal(err)
+  }
+  if err := pandit.RegisterEnginesFromEnv(); err != nil {
+    log.Fatal(err)
+  }
+  defaultCalculator.Engine, err = pandit.ParseEngine(os.Getenv("SHUBH_ENGINE"))
+  if err != nil {
+    log.Fatal(err)
+  }
+  defaultCalculator.Definition, err = pandit.SunriseDefinitionFromEnv()
+  if err != nil {
+    log.Fatal(err)
+  }
+
+  // Requests copy the calculator, so they all share this cache
+  defaultCalculator.Cache = pandit.NewEphemerisCache(pandit.DEFAULT_CACHE_SIZE)
+  defaultCalculator.Location.Timezone = defaultTimezone
+  go defaultCalculator.WarmCache(time.Now(), pandit.CACHE_WARM_DAYS)
+  go defaultCalculator.RefreshCache(pandit.CACHE_WARM_DAYS, nil)
+
   http.HandleFunc("/chowgadhiya", getChowgadhiyaResponse) // set router
+  http.HandleFunc("/v1/day", getDayResponse)
+  http.HandleFunc("/v1/periods", getPeriodsResponse)
+  http.HandleFunc("/v1/cities", getCitiesResponse)
+  http.HandleFunc("/v1/cache", getCacheResponse)
+  http.HandleFunc("/v1/rahukaal", getRahuKaalResponse)
+  http.HandleFunc("/v1/muhurat", getMuhuratResponse)
   addr, err := determineListenAddress()
   if err != nil {
     log.Fatal(err)
@@ -0,0 +1,443 @@
+package main
+
+import (
+  "fmt"
+  "net/http"
+  "strconv"
+  "time"
+
+  "shubhcron-pandit/pandit"
+)
+
+type PeriodResponse struct {
+  Name     string `json:"name"`
+  Phase    string `json:"phase"`
+  Vaar     string `json:"vaar"`
+  Start    int64  `json:"start"`
+  End      int64  `json:"end"`
+  Duration int64  `json:"duration"`
+  IsShubh  bool   `json:"shubh"`
+  Fallback string `json:"fallback,omitempty"`
+  // The kaal that falls on it, if any
+  Kaal     string `json:"kaal,omitempty"`
+}
+
+type DayResponse struct {
+  Date        string             `json:"date"`
+  Vaar        string             `json:"vaar"`
+  Policy      string             `json:"policy"`
+  Engine      string             `json:"engine"`
+  Definition  DefinitionResponse `json:"definition"`
+  Sunrise     int64              `json:"sunrise"`
+  Sunset      int64              `json:"sunset"`
+  NextSunrise int64              `json:"nextSunrise"`
+  Fallback    string             `json:"fallback,omitempty"`
+  Periods     []PeriodResponse   `json:"periods"`
+  Kaals       []KaalResponse     `json:"kaals"`
+  Muhurats    []MuhuratResponse  `json:"muhurats"`
+}
+
+func newPeriodResponse(p pandit.Period, policy pandit.Policy) PeriodResponse {
+  return PeriodResponse{
+    Name:     p.Chowgadhiya.String(),
+    Phase:    p.Phase.String(),
+    Vaar:     p.Vaar.String(),
+    Start:    p.Start.Unix(),
+    End:      p.End.Unix(),
+    Duration: int64(p.Duration().Seconds()),
+    IsShubh:  policy.IsShubh(p),
+    Fallback: fallbackName(p.Fallback),
+    Kaal:     kaalName(p.Kaal),
+  }
+}
+
+// Empty when no kaal falls on the period, so it is left out of responses
+func kaalName(k pandit.KaalType) string {
+  if k == pandit.NoKaal {
+    return ""
+  }
+  return k.String()
+}
+
+/**
+ * GET /v1/day?date=2026-10-18
+ * Lists all 16 chowgadhiyas, the kaals and the muhurats
+ * of the vedic day starting at sunrise on date (default today)
+ */
+func getDayResponse(w http.ResponseWriter, r *http.Request) {
+  calculator, timezone, err := calculatorForRequest(r)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+
+  date := time.Now().In(timezone)
+  if value := r.URL.Query().Get("date"); value != "" {
+    parsed, err := time.ParseInLocation("2006-01-02", value, timezone)
+    if err != nil {
+      writeError(w, &queryError{"date", "expected YYYY-MM-DD"})
+      return
+    }
+    date = parsed
+  }
+
+  day, err := calculator.VedicDayOn(date)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+
+  response := DayResponse{
+    Date:        date.Format("2006-01-02"),
+    Vaar:        day.Vaar.String(),
+    Policy:      calculator.Policy.String(),
+    Engine:      calculator.Engine.Name(),
+    Definition:  newDefinitionResponse(calculator.Definition),
+    Sunrise:     day.Sunrise.Unix(),
+    Sunset:      day.Sunset.Unix(),
+    NextSunrise: day.NextSunrise.Unix(),
+    Fallback:    fallbackName(day.Fallback),
+    Kaals:       []KaalResponse{},
+    Muhurats:    []MuhuratResponse{},
+  }
+  for _, period := range day.Periods() {
+    response.Periods = append(response.Periods, newPeriodResponse(period, calculator.Policy))
+  }
+  now := time.Now()
+  for _, kaal := range day.Kaals() {
+    response.Kaals = append(response.Kaals, newKaalResponse(kaal, now))
+  }
+  for _, muhurat := range day.Muhurats() {
+    response.Muhurats = append(response.Muhurats, newMuhuratResponse(muhurat, now))
+  }
+
+  writeJSON(w, http.StatusOK, response)
+}
+
+type KaalResponse struct {
+  Name     string `json:"name"`
+  Vaar     string `json:"vaar"`
+  Start    int64  `json:"start"`
+  End      int64  `json:"end"`
+  Duration int64  `json:"duration"`
+  // Whether the instant the response was evaluated at falls in it
+  Active   bool   `json:"active"`
+  Fallback string `json:"fallback,omitempty"`
+}
+
+func newKaalResponse(p pandit.KaalPeriod, at time.Time) KaalResponse {
+  return KaalResponse{
+    Name:     p.Kaal.String(),
+    Vaar:     p.Vaar.String(),
+    Start:    p.Start.Unix(),
+    End:      p.End.Unix(),
+    Duration: int64(p.Duration().Seconds()),
+    Active:   p.Contains(at),
+    Fallback: fallbackName(p.Fallback),
+  }
+}
+
+type MuhuratResponse struct {
+  Name     string `json:"name"`
+  Vaar     string `json:"vaar"`
+  Start    int64  `json:"start"`
+  End      int64  `json:"end"`
+  Duration int64  `json:"duration"`
+  Active   bool   `json:"active"`
+  Fallback string `json:"fallback,omitempty"`
+}
+
+func newMuhuratResponse(p pandit.MuhuratPeriod, at time.Time) MuhuratResponse {
+  return MuhuratResponse{
+    Name:     p.Muhurat.String(),
+    Vaar:     p.Vaar.String(),
+    Start:    p.Start.Unix(),
+    End:      p.End.Unix(),
+    Duration: int64(p.Duration().Seconds()),
+    Active:   p.Contains(at),
+    Fallback: fallbackName(p.Fallback),
+  }
+}
+
+/**
+ * Reads ?at= (default now) and ?date=, only one of which may be
+ * given. With date, day is the vedic day starting on that date
+ */
+func parseAtOrDate(r *http.Request, calculator *pandit.Calculator, timezone *time.Location) (now time.Time, day *pandit.VedicDay, err error) {
+  query := r.URL.Query()
+  now = time.Now().In(timezone)
+  if query.Get("at") != "" && query.Get("date") != "" {
+    return now, nil, &queryError{"date", "give either at or date, not both"}
+  }
+  if value := query.Get("at"); value != "" {
+    now, err = parseTimeParam("at", value, timezone)
+    return now, nil, err
+  }
+  if value := query.Get("date"); value != "" {
+    date, err := time.ParseInLocation("2006-01-02", value, timezone)
+    if err != nil {
+      return now, nil, &queryError{"date", "expected YYYY-MM-DD"}
+    }
+    vedicDay, err := calculator.VedicDayOn(date)
+    if err != nil {
+      return now, nil, err
+    }
+    return now, &vedicDay, nil
+  }
+  return now, nil, nil
+}
+
+/**
+ * GET /v1/rahukaal[?at=...|?date=2026-10-18]
+ * The rahu kaal in progress at the instant (default now) or the
+ * next one, or with date the one of the vedic day starting that date
+ */
+func getRahuKaalResponse(w http.ResponseWriter, r *http.Request) {
+  calculator, timezone, err := calculatorForRequest(r)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+
+  now, day, err := parseAtOrDate(r, calculator, timezone)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+
+  var period pandit.KaalPeriod
+  if day != nil {
+    period = day.Kaal(pandit.RahuKaal)
+  } else {
+    period, err = calculator.NextKaal(pandit.RahuKaal, now)
+    if err != nil {
+      writeError(w, err)
+      return
+    }
+  }
+
+  writeJSON(w, http.StatusOK, newKaalResponse(period, now))
+}
+
+/**
+ * GET /v1/muhurat?name=brahma[&at=...|&date=2026-10-18]
+ * Like /v1/rahukaal, for abhijit, brahma or one of the sandhyas.
+ * A date without that muhurat (abhijit on budhvaar) is a 422
+ */
+func getMuhuratResponse(w http.ResponseWriter, r *http.Request) {
+  calculator, timezone, err := calculatorForRequest(r)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+
+  muhurat, err := pandit.ParseMuhurat(r.URL.Query().Get("name"))
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+
+  now, day, err := parseAtOrDate(r, calculator, timezone)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+
+  var period pandit.MuhuratPeriod
+  if day != nil {
+    var ok bool
+    period, ok = day.Muhurat(muhurat)
+    if !ok {
+      writeError(w, &pandit.Error{Err: pandit.ErrNoMuhurat, Detail: fmt.Sprintf("%s on %s", muhurat, day.Vaar)})
+      return
+    }
+  } else {
+    period, err = calculator.NextMuhurat(muhurat, now)
+    if err != nil {
+      writeError(w, err)
+      return
+    }
+  }
+
+  writeJSON(w, http.StatusOK, newMuhuratResponse(period, now))
+}
+
+type PeriodsResponse struct {
+  From       int64              `json:"from"`
+  To         int64              `json:"to"`
+  Policy     string             `json:"policy"`
+  Engine     string             `json:"engine"`
+  Definition DefinitionResponse `json:"definition"`
+  Periods    []PeriodResponse   `json:"periods"`
+  // Pass as from to fetch the next page, absent on the last page.
+  // Set on a short page too when the span walked ran out first
+  Next       *int64             `json:"next,omitempty"`
+}
+
+const DEFAULT_PAGE_SIZE = 200
+const MAX_PAGE_SIZE = 2000
+
+// How far one page of /v1/periods walks from its from, so a filter
+// that matches little or nothing cannot walk centuries in one request
+const MAX_PAGE_SPAN_DAYS = 366
+
+/**
+ * Accepts RFC3339, a plain YYYY-MM-DD date (midnight in timezone)
+ * or unix seconds. Calculations happen in the requested
+ * timezone whatever offset the value came with
+ */
+func parseTimeParam(name string, value string, timezone *time.Location) (time.Time, error) {
+  if t, err := time.Parse(time.RFC3339, value); err == nil {
+    return t.In(timezone), nil
+  }
+  if t, err := time.ParseInLocation("2006-01-02", value, timezone); err == nil {
+    return t, nil
+  }
+  if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+    return time.Unix(seconds, 0).In(timezone), nil
+  }
+  return time.Time{}, &queryError{name, "expected RFC3339, YYYY-MM-DD or unix seconds"}
+}
+
+/**
+ * GET /v1/periods?from=...&to=...[&shubh=true][&limit=200]
+ * Lists every period overlapping from..to, a page at a time.
+ * A page stops after limit periods or MAX_PAGE_SPAN_DAYS, so
+ * with shubh=true it can be short or even empty and still have next
+ */
+func getPeriodsResponse(w http.ResponseWriter, r *http.Request) {
+  query := r.URL.Query()
+
+  calculator, timezone, err := calculatorForRequest(r)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+
+  from, err := parseTimeParam("from", query.Get("from"), timezone)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+  to, err := parseTimeParam("to", query.Get("to"), timezone)
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+  if !from.Before(to) {
+    writeError(w, &queryError{"to", "must be after from"})
+    return
+  }
+
+  onlyShubh := query.Get("shubh") == "true"
+
+  limit := DEFAULT_PAGE_SIZE
+  if value := query.Get("limit"); value != "" {
+    limit, err = strconv.Atoi(value)
+    if err != nil || limit < 1 || limit > MAX_PAGE_SIZE {
+      writeError(w, &queryError{"limit", fmt.Sprintf("expected a number between 1 and %d", MAX_PAGE_SIZE)})
+      return
+    }
+  }
+
+  response := PeriodsResponse{
+    From:       from.Unix(),
+    To:         to.Unix(),
+    Policy:     calculator.Policy.String(),
+    Engine:     calculator.Engine.Name(),
+    Definition: newDefinitionResponse(calculator.Definition),
+    Periods:    []PeriodResponse{},
+  }
+
+  stop := from.AddDate(0, 0, MAX_PAGE_SPAN_DAYS)
+  err = calculator.Periods(from, to, func(period pandit.Period) bool {
+    if !period.Start.Before(stop) {
+      next := period.Start.Unix()
+      response.Next = &next
+      return false
+    }
+    if onlyShubh && !calculator.Policy.IsShubh(period) {
+      return true
+    }
+    if len(response.Periods) == limit {
+      next := period.Start.Unix()
+      response.Next = &next
+      return false
+    }
+    response.Periods = append(response.Periods, newPeriodResponse(period, calculator.Policy))
+    return true
+  })
+  if err != nil {
+    writeError(w, err)
+    return
+  }
+
+  writeJSON(w, http.StatusOK, response)
+}
+
+type CityResponse struct {
+  Name      string   `json:"name"`
+  Aliases   []string `json:"aliases"`
+  Country   string   `json:"country"`
+  Latitude  float64  `json:"latitude"`
+  Longitude float64  `json:"longitude"`
+  Elevation float64  `json:"elevation"`
+  Timezone  string   `json:"timezone"`
+}
+
+type CitiesResponse struct {
+  Cities []CityResponse `json:"cities"`
+}
+
+/**
+ * GET /v1/cities?q=beng[&limit=20]
+ * Searches the offline gazetteer, best matches first
+ */
+func getCitiesResponse(w http.ResponseWriter, r *http.Request) {
+  query := r.URL.Query()
+
+  limit := DEFAULT_PAGE_SIZE
+  if value := query.Get("limit"); value != "" {
+    var err error
+    limit, err = strconv.Atoi(value)
+    if err != nil || limit < 1 || limit > MAX_PAGE_SIZE {
+      writeError(w, &queryError{"limit", fmt.Sprintf("expected a number between 1 and %d", MAX_PAGE_SIZE)})
+      return
+    }
+  }
+
+  response := CitiesResponse{Cities: []CityResponse{}}
+  for _, city := range pandit.SearchCities(query.Get("q"), limit) {
+    aliases := city.Aliases
+    if aliases == nil {
+      aliases = []string{}
+    }
+    response.Cities = append(response.Cities, CityResponse{
+      Name:      city.Name,
+      Aliases:   aliases,
+      Country:   city.Country,
+      Latitude:  city.Latitude,
+      Longitude: city.Longitude,
+      Elevation: city.Elevation,
+      Timezone:  city.Timezone,
+    })
+  }
+
+  writeJSON(w, http.StatusOK, response)
+}
+
+type CacheResponse struct {
+  Hits     uint64 `json:"hits"`
+  Misses   uint64 `json:"misses"`
+  Size     int    `json:"size"`
+  Capacity int    `json:"capacity"`
+}
+
+/**
+ * GET /v1/cache
+ * Hit and miss counters of the shared ephemeris cache
+ */
+func getCacheResponse(w http.ResponseWriter, r *http.Request) {
+  stats := defaultCalculator.Cache.Stats()
+  writeJSON(w, http.StatusOK, CacheResponse{stats.Hits, stats.Misses, stats.Size, stats.Capacity})
+}